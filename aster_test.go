@@ -1,7 +1,7 @@
 //
 // aster :: aster_test.go
 //
-//   Copyright (c) 2014-2021 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -11,9 +11,16 @@ package aster_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -69,6 +76,120 @@ func TestIgnore(t *testing.T) {
 	}
 }
 
+func TestIgnoreFile(t *testing.T) {
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.ignore.push(/\.pb\.go$/);
+			aster.tempFiles.push(/\.bak$/);
+			aster.watch(/.+/, function(files) {
+				cycles.push(files.sort());
+			});
+		`),
+		before: func(a *aster.Aster, _ context.CancelFunc) {
+			a.Eval(`var cycles = [];`)
+		},
+		test: func(d time.Duration, _ context.CancelFunc) {
+			for _, n := range []string{"a.go", "a.pb.go", ".a.go.swp", "a.go~", "4913", ".#a.go", "a.go.bak"} {
+				sh.Touch(n)
+			}
+			time.Sleep(d)
+		},
+		after: func(a *aster.Aster, _ *aster.Watcher) {
+			v, _ := a.Eval(`cycles.join(';');`)
+			if g, e := v.String(), "a.go"; g != e {
+				t.Fatalf("expected %q, got %q", e, g)
+			}
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stderr, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
+func TestIgnoreVCS(t *testing.T) {
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.ignoreVCS = true;
+			aster.watch(/.+\.go$/, function(files) {
+				cycles.push(files);
+			});
+		`),
+		before: func(a *aster.Aster, _ context.CancelFunc) {
+			sh.Mkdir("vendor")
+			sh.Mkdir("src")
+			os.WriteFile(".gitignore", []byte("/vendor/\n*_gen.go\n"), 0o666)
+			a.Eval(`var cycles = [];`)
+		},
+		test: func(d time.Duration, _ context.CancelFunc) {
+			sh.Touch("a.go")
+			sh.Touch("a_gen.go")
+			sh.Touch("src", "b_gen.go")
+			time.Sleep(d)
+
+			os.WriteFile(".gitignore", []byte("*_gen.go\n"), 0o666)
+			time.Sleep(d)
+		},
+		after: func(a *aster.Aster, w *aster.Watcher) {
+			if g, e := w.Paths(), []string{".", "src", "vendor"}; !reflect.DeepEqual(g, e) {
+				t.Fatalf("expected %v, got %v", e, g)
+			}
+			v, _ := a.Eval(`cycles.join(';');`)
+			if g, e := v.String(), "a.go"; g != e {
+				t.Fatalf("expected %q, got %q", e, g)
+			}
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stderr, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
+func TestCloseWithoutWatch(t *testing.T) {
+	err := test.Sandbox(func() error {
+		if err := test.Gen(``); err != nil {
+			return err
+		}
+		a, err := test.New()
+		if err != nil {
+			return err
+		}
+		w, err := aster.NewWatcher(context.Background(), a)
+		if err != nil {
+			return err
+		}
+		done := make(chan error, 1)
+		go func() {
+			done <- w.Close()
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Error(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout")
+		}
+		if err := w.Close(); err != nil {
+			t.Error(err)
+		}
+		if err := w.Watch(); err != nil {
+			t.Error(err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
 func TestInterrupt(t *testing.T) {
 	at := &asterTest{
 		src: ``,
@@ -83,35 +204,785 @@ func TestInterrupt(t *testing.T) {
 			time.Sleep(d)
 		},
 		after: func(_ *aster.Aster, w *aster.Watcher) {
-			if err := w.Close(); err != nil {
-				t.Fatal(err)
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+		},
+	}
+	if _, err := at.Run(); err != context.Canceled {
+		t.Fatal(err)
+	}
+
+	at.before = nil
+	if _, err := at.Run(); err != context.Canceled {
+		t.Fatal(err)
+	}
+}
+
+func TestTerminate(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "otto_cmd.exe")
+	out, err := exec.Command("go", "build", "-o", exe, "otto_test_cmd.go").CombinedOutput()
+	if err != nil {
+		t.Fatalf("build failed\n%s", out)
+	}
+
+	at := &asterTest{
+		src: cli.Dedent(fmt.Sprintf(`
+			var os = require('os');
+			aster.watch(/.+\.go$/, function() {
+			  os.system([%q, '-sleep', '10s'], { stdout: null });
+			  os.system([%[1]q], { stdout: null });
+			});
+		`, exe)),
+		test: func(d time.Duration, cancel context.CancelFunc) {
+			sh.Touch("a.go")
+			time.Sleep(d)
+
+			cancel()
+		},
+	}
+	start := time.Now()
+	stderr, err := at.Run()
+	if err != context.Canceled {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("took %v", d)
+	}
+	lines := strings.SplitN(stderr, "\n", 2)
+	if g, e := lines[0], "aster.test: Error: terminated"; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	at := &asterTest{
+		src: cli.Dedent(`
+			var os = require('os');
+			aster.watch(/.+\.go$/, function(files) {
+			  os.mkdir('dir');
+			  os.rename(files[0], 'b.go');
+			  var f = new os.open('out.txt', 'w');
+			  f.write('out');
+			  f.close();
+			  os.system(['go', 'test', './...'], { dir: '.' });
+			  os.remove(files[0]);
+			});
+		`),
+		options: []aster.Option{aster.DryRun(true)},
+		test: func(d time.Duration, cancel context.CancelFunc) {
+			sh.Touch("a.go")
+			time.Sleep(d)
+
+			cancel()
+		},
+		after: func(*aster.Aster, *aster.Watcher) {
+			for _, n := range []string{"a.go"} {
+				if _, err := os.Stat(n); err != nil {
+					t.Error(err)
+				}
+			}
+			for _, n := range []string{"b.go", "dir", "out.txt"} {
+				if _, err := os.Stat(n); err == nil {
+					t.Errorf("%v exists", n)
+				}
+			}
+		},
+	}
+	stderr, err := at.Run()
+	if err != context.Canceled {
+		t.Fatal(err)
+	}
+	if g, e := stderr, cli.Dedent(`
+		aster.test: dry-run: batch: a.go
+		aster.test: dry-run: watch /.+\.go$/: a.go
+		aster.test: dry-run: mkdir dir
+		aster.test: dry-run: rename a.go b.go
+		aster.test: dry-run: open out.txt
+		aster.test: dry-run: system go test ./... (in .)
+		aster.test: dry-run: remove a.go
+		aster.test: context canceled
+	`); g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
+func TestLog(t *testing.T) {
+	var b bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&b, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.watch(/.+\.go$/, function() {});
+		`),
+		options: []aster.Option{aster.Log(l)},
+		test: func(d time.Duration, cancel context.CancelFunc) {
+			sh.Touch("a.go~")
+			sh.Touch("a.go")
+			time.Sleep(d)
+
+			cancel()
+		},
+	}
+	if _, err := at.Run(); err != context.Canceled {
+		t.Fatal(err)
+	}
+	for _, e := range []string{
+		`level=DEBUG msg=event name=a.go op=CREATE`,
+		`level=DEBUG msg=ignore name=a.go~ pattern=/~$/`,
+		`level=DEBUG msg=squash name=a.go delay=101ms`,
+		`level=INFO msg=batch files=[a.go]`,
+		`level=INFO msg=match watch=/.+\.go$/ files=[a.go]`,
+	} {
+		if !strings.Contains(b.String(), e+"\n") {
+			t.Errorf("%q not found in %q", e, b.String())
+		}
+	}
+}
+
+func TestJSONLog(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "otto_cmd.exe")
+	out, err := exec.Command("go", "build", "-o", exe, "otto_test_cmd.go").CombinedOutput()
+	if err != nil {
+		t.Fatalf("build failed\n%s", out)
+	}
+
+	var b bytes.Buffer
+	at := &asterTest{
+		src: cli.Dedent(fmt.Sprintf(`
+			var os = require('os');
+			aster.watch(/.+\.go$/, function() {
+			  os.system([%q, '-code', '1'], { stdout: null, stderr: null });
+			  aster.notify('failure', 'title', 'text');
+			});
+		`, exe)),
+		// without notifier
+		options: []aster.Option{aster.JSONLog(&b)},
+		test: func(d time.Duration, cancel context.CancelFunc) {
+			sh.Touch("a.go")
+			time.Sleep(d)
+
+			cancel()
+		},
+	}
+	if _, err := at.Run(); err != context.Canceled {
+		t.Fatal(err)
+	}
+
+	var r aster.Record
+	if err := json.Unmarshal(b.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+	if g, e := r.Type, "change"; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+	if g, e := r.Files, []string{"a.go"}; !reflect.DeepEqual(g, e) {
+		t.Errorf("expected %v, got %v", e, g)
+	}
+	if len(r.Watches) != 1 {
+		t.Fatalf("unexpected watches: %v", r.Watches)
+	}
+	w := r.Watches[0]
+	if g, e := w.Pattern, `/.+\.go$/`; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+	if len(w.Commands) != 1 {
+		t.Fatalf("unexpected commands: %v", w.Commands)
+	}
+	if g, e := w.Commands[0].Exit, 1; g != e {
+		t.Errorf("expected %v, got %v", e, g)
+	}
+	if g, e := w.Notifications, []*aster.NotifyRecord{{Name: "failure", Title: "title", Body: "text"}}; !reflect.DeepEqual(g, e) {
+		t.Errorf("expected %v, got %v", e, g)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	n := test.NewNotifier()
+
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.watch(/.+\.go$/, function() {
+				aster.notify("success", "title", "text");
+			});
+		`),
+		notifier: n,
+		test: func(d time.Duration, _ context.CancelFunc) {
+			sh.Touch("a.go")
+			time.Sleep(d)
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stderr, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
+func TestNotifyError(t *testing.T) {
+	n := test.NewNotifier()
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.watch(/.+\.go$/, function() {
+				aster.notify("success", "title", "text");
+			});
+		`),
+		notifier: n,
+		test: func(d time.Duration, _ context.CancelFunc) {
+			sh.Touch("a.go")
+			time.Sleep(d)
+		},
+	}
+
+	e := fmt.Errorf("Notify error")
+	n.MockCall("Notify", e)
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitN(stderr, "\n", 2)
+	if g, e := lines[0], fmt.Sprintf("aster.test: %v", e); g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
+func TestPause(t *testing.T) {
+	for _, drop := range []bool{false, true} {
+		at := &asterTest{
+			src: cli.Dedent(fmt.Sprintf(`
+				aster.watch(/.+\.go$/, function(files) {
+				  cycles.push(files.sort());
+				});
+				aster.watch(/^pause$/, function() {
+				  aster.dropOnResume = %v;
+				  aster.pause();
+				});
+			`, drop)),
+			before: func(a *aster.Aster, _ context.CancelFunc) {
+				a.Eval(`var cycles = [];`)
+			},
+			test: func(d time.Duration, _ context.CancelFunc) {
+				sh.Touch("pause")
+				time.Sleep(d)
+				sh.Touch("a.go")
+				time.Sleep(d)
+				sh.Touch("b.go")
+				time.Sleep(d)
+			},
+			after: func(a *aster.Aster, w *aster.Watcher) {
+				if !w.Paused() {
+					t.Fatal("expected to be paused")
+				}
+				v, _ := a.Eval(`cycles.join(';');`)
+				if g, e := v.String(), ""; g != e {
+					t.Errorf("expected %q, got %q", e, g)
+				}
+
+				w.Resume()
+				time.Sleep(101 * time.Millisecond)
+				e := "a.go,b.go"
+				if drop {
+					e = ""
+				}
+				v, _ = a.Eval(`cycles.join(';');`)
+				if g := v.String(); g != e {
+					t.Errorf("expected %q, got %q", e, g)
+				}
+			},
+		}
+		stderr, err := at.Run()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g, e := stderr, ""; g != e {
+			t.Errorf("expected %q, got %q", e, g)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	r, pw := io.Pipe()
+	defer pw.Close()
+
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.watch(/.+\.go$/, function(files) {
+			  cycles.push(files.sort());
+			});
+		`),
+		before: func(a *aster.Aster, _ context.CancelFunc) {
+			for _, n := range []string{"a.go", "b.go", "a.go~"} {
+				sh.Touch(n)
+			}
+			a.Eval(`var cycles = [];`)
+		},
+		watch: func(w *aster.Watcher) {
+			w.Read(r)
+		},
+		test: func(d time.Duration, _ context.CancelFunc) {
+			io.WriteString(pw, "a.go\x00b.go\n")
+			time.Sleep(d)
+			// not watched
+			sh.Touch("c.go")
+			time.Sleep(d)
+
+			io.WriteString(pw, `{"path": "b.go"}`+"\n")
+			io.WriteString(pw, `{"path": "a.go~", "op": "create"}`+"\n")
+			io.WriteString(pw, `{"path": "d.go", "op": "remove"}`+"\n")
+			time.Sleep(d)
+		},
+		after: func(a *aster.Aster, _ *aster.Watcher) {
+			v, _ := a.Eval(`cycles.join(';');`)
+			if g, e := v.String(), "a.go,b.go;b.go"; g != e {
+				t.Errorf("expected %q, got %q", e, g)
+			}
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stderr, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
+func TestReadEOF(t *testing.T) {
+	err := test.Sandbox(func() error {
+		src := cli.Dedent(`
+			aster.ignore.push(/^vendor$/);
+			aster.watch(/.+\.go$/, function(files) {
+			  cycles.push(files.sort());
+			});
+		`)
+		if err := test.Gen(src); err != nil {
+			return err
+		}
+		if err := sh.Mkdir("vendor", "x"); err != nil {
+			return err
+		}
+		for _, n := range []string{"a.go", "b.go", filepath.Join("vendor", "x", "x.go")} {
+			if err := sh.Touch(n); err != nil {
+				return err
+			}
+		}
+		a, err := test.New()
+		if err != nil {
+			return err
+		}
+		a.Eval(`var cycles = [];`)
+		w, err := aster.NewWatcher(context.Background(), a, aster.External(true))
+		if err != nil {
+			return err
+		}
+		defer w.Close()
+
+		if g := w.Paths(); len(g) != 0 {
+			t.Errorf("expected no paths, got %v", g)
+		}
+		w.Squash = time.Hour
+		w.Read(strings.NewReader("a.go\nvendor/x/x.go\nb.go\n"))
+		done := make(chan error, 1)
+		go func() {
+			done <- w.Watch()
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Error(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout")
+		}
+		v, _ := a.Eval(`cycles.join(';');`)
+		if g, e := v.String(), "a.go,b.go"; g != e {
+			t.Errorf("expected %q, got %q", e, g)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestState(t *testing.T) {
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.watch(/.+\.go$/, function(files) {
+			  cycles.push(files.sort());
+			});
+		`),
+		before: func(a *aster.Aster, _ context.CancelFunc) {
+			for _, n := range []string{"a.go", "b.go", "d.go"} {
+				sh.Touch(n)
+			}
+			a.Eval(`var cycles = [];`)
+
+			w, err := aster.NewWatcher(context.Background(), a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			go w.Watch()
+			if err := w.SaveState(aster.StateFile, true); err != nil {
+				t.Fatal(err)
+			}
+			w.Close()
+
+			// same content
+			mtime := time.Now().Add(time.Hour)
+			os.Chtimes("a.go", mtime, mtime)
+			os.WriteFile("b.go", []byte("package b\n"), 0o666)
+			sh.Touch("c.go")
+			// not processed
+			os.Remove("d.go")
+		},
+		watch: func(w *aster.Watcher) {
+			if err := w.CatchUp(aster.StateFile); err != nil {
+				t.Fatal(err)
+			}
+		},
+		test: func(d time.Duration, _ context.CancelFunc) {
+			time.Sleep(d)
+		},
+		after: func(a *aster.Aster, _ *aster.Watcher) {
+			v, _ := a.Eval(`cycles.join(';');`)
+			if g, e := v.String(), "b.go,c.go"; g != e {
+				t.Errorf("expected %q, got %q", e, g)
+			}
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stderr, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
+func TestDigest(t *testing.T) {
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.watch(/.+\.go$/, function(files) {
+			  cycles.push(files.sort());
+			});
+		`),
+		before: func(a *aster.Aster, _ context.CancelFunc) {
+			if err := os.WriteFile("a.go", []byte("package a\n"), 0o666); err != nil {
+				t.Fatal(err)
+			}
+			a.Eval(`var cycles = [];`)
+		},
+		watch: func(w *aster.Watcher) {
+			w.Digests = 16
+		},
+		test: func(d time.Duration, _ context.CancelFunc) {
+			time.Sleep(d)
+			for _, s := range []string{"package a\n", "package a\n", "package b\n", "package b\n"} {
+				os.WriteFile("a.go", []byte(s), 0o666)
+				time.Sleep(d)
+			}
+			// restored
+			os.Remove("a.go")
+			time.Sleep(d)
+			os.WriteFile("a.go", []byte("package b\n"), 0o666)
+			time.Sleep(d)
+		},
+		after: func(a *aster.Aster, _ *aster.Watcher) {
+			v, _ := a.Eval(`cycles.join(';');`)
+			if g, e := v.String(), "a.go;a.go"; g != e {
+				t.Errorf("expected %q, got %q", e, g)
+			}
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stderr, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
+func TestDigests(t *testing.T) {
+	err := test.Sandbox(func() error {
+		for _, n := range []string{"a.go", "b.go", "c.go"} {
+			if err := os.WriteFile(n, []byte("package "+n[:1]+"\n"), 0o666); err != nil {
+				return err
+			}
+		}
+		dc := aster.NewDigests(2)
+		for _, n := range []string{"a.go", "b.go", "c.go"} {
+			if !dc.Changed(n) {
+				t.Errorf("%v: expected to be changed", n)
+			}
+		}
+		if g, e := dc.Len(), 2; g != e {
+			t.Errorf("expected %v, got %v", e, g)
+		}
+		// a.go has been evicted
+		for _, tt := range []struct {
+			name    string
+			changed bool
+		}{
+			{"a.go", true},
+			{"c.go", false},
+			{"b.go", true},
+		} {
+			if g, e := dc.Changed(tt.name), tt.changed; g != e {
+				t.Errorf("%v: expected %v, got %v", tt.name, e, g)
+			}
+		}
+		if g, e := dc.Len(), 2; g != e {
+			t.Errorf("expected %v, got %v", e, g)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOutputs(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "otto_cmd.exe")
+	out, err := exec.Command("go", "build", "-o", exe, "otto_test_cmd.go").CombinedOutput()
+	if err != nil {
+		t.Fatalf("build failed\n%s", out)
+	}
+
+	at := &asterTest{
+		src: cli.Dedent(fmt.Sprintf(`
+			var os = require('os');
+			aster.watch(/\.txt$/, function(files) {
+			  cycles.push('txt:' + files);
+			  var f = new os.open('b.txt.tmp', 'w');
+			  f.write('b');
+			  f.close();
+			  os.rename('b.txt.tmp', 'b.txt');
+			  f = new os.open('a.html.tmp', 'w');
+			  f.write('a');
+			  f.close();
+			  os.rename('a.html.tmp', 'a.html');
+			});
+			aster.watch(/\.html$/, function(files) {
+			  cycles.push('html:' + files);
+			});
+			aster.watch(/\.log$/, function(files) {
+			  cycles.push('log:' + files);
+			  touch('c.log');
+			}, {
+			  outputs: ['c.log'],
+			});
+			aster.watch(/\.go$/, function(files) {
+			  cycles.push('go:' + files);
+			  os.system([%q, '-write', 'b.go'], { stdout: null });
+			});
+		`, exe)),
+		before: func(a *aster.Aster, _ context.CancelFunc) {
+			a.Eval(`var cycles = [];`)
+			// not tracked
+			a.Set("touch", func(name string) {
+				sh.Touch(name)
+			})
+		},
+		test: func(d time.Duration, _ context.CancelFunc) {
+			sh.Touch("a.txt")
+			time.Sleep(d * 2)
+
+			sh.Touch("a.log")
+			time.Sleep(d)
+			// modified after the callback
+			sh.Touch("c.log")
+			time.Sleep(d * 2)
+
+			sh.Touch("a.go")
+			time.Sleep(d * 2)
+		},
+		after: func(a *aster.Aster, _ *aster.Watcher) {
+			v, _ := a.Eval(`cycles.join(';');`)
+			if g, e := v.String(), "txt:a.txt;html:a.html;log:a.log;log:c.log;go:a.go"; g != e {
+				t.Errorf("expected %q, got %q", e, g)
+			}
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stderr, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
+func TestLoop(t *testing.T) {
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.loopLimit = 1;
+			aster.watch(/\.txt$/, function(files) {
+			  cycles.push(files);
+			  if (cycles.length < 3) {
+			    touch('a.txt');
+			  }
+			});
+		`),
+		before: func(a *aster.Aster, _ context.CancelFunc) {
+			a.Eval(`var cycles = [];`)
+			// not tracked
+			a.Set("touch", func(name string) {
+				sh.Touch(name)
+			})
+		},
+		test: func(d time.Duration, _ context.CancelFunc) {
+			sh.Touch("a.txt")
+			time.Sleep(d * 4)
+		},
+		after: func(a *aster.Aster, _ *aster.Watcher) {
+			v, _ := a.Eval(`cycles.join(';');`)
+			if g, e := v.String(), "a.txt;a.txt;a.txt"; g != e {
+				t.Errorf("expected %q, got %q", e, g)
+			}
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stderr, "aster.test: aster.watch: /\\.txt$/ (Asterfile:2:1) has triggered itself 2 times in a row\n"; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
+func TestReload(t *testing.T) {
+	at := &asterTest{
+		src: ``,
+		test: func(d time.Duration, _ context.CancelFunc) {
+			if err := sh.Mkdir("build"); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(d)
+
+			sh.Touch("a.go")
+
+			src := `aster.ignore.push(/^build$/);`
+			if err := test.Gen(src); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(d)
+
+			src = `++;`
+			if err := test.Gen(src); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(d)
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitN(stderr, "\n", 2)
+	if g, e := lines[0], "aster.test: failed to reload"; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
+func TestReloadRequest(t *testing.T) {
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.watch(/.+\.go$/, function() {});
+		`),
+		before: func(a *aster.Aster, _ context.CancelFunc) {
+			a.Eval(`var marker = true;`)
+		},
+		test: func(d time.Duration, _ context.CancelFunc) {
+			time.Sleep(d)
+		},
+		after: func(a *aster.Aster, w *aster.Watcher) {
+			w.Reload()
+			time.Sleep(101 * time.Millisecond)
+			v, _ := a.Eval(`typeof marker;`)
+			if g, e := v.String(), "undefined"; g != e {
+				t.Errorf("expected %q, got %q", e, g)
+			}
+			if g, e := a.NumWatches(), 2; g != e {
+				t.Errorf("expected %v, got %v", e, g)
+			}
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stderr, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
+func TestRemoveDir(t *testing.T) {
+	at := &asterTest{
+		src: ``,
+		before: func(*aster.Aster, context.CancelFunc) {
+			sh.Mkdir("a", "b", "c")
+			sh.Mkdir("a", "d")
+			sh.Mkdir("ab")
+			sh.Mkdir("e")
+		},
+		test: func(d time.Duration, _ context.CancelFunc) {
+			os.RemoveAll("a")
+			os.Rename("e", "f")
+			time.Sleep(d)
+		},
+		after: func(_ *aster.Aster, w *aster.Watcher) {
+			if g, e := w.Paths(), []string{".", "ab", "f"}; !reflect.DeepEqual(g, e) {
+				t.Fatalf("expected %v, got %v", e, g)
 			}
 		},
 	}
-	if _, err := at.Run(); err != context.Canceled {
+	stderr, err := at.Run()
+	if err != nil {
 		t.Fatal(err)
 	}
-
-	at.before = nil
-	if _, err := at.Run(); err != context.Canceled {
-		t.Fatal(err)
+	if g, e := stderr, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
 	}
 }
 
-func TestNotify(t *testing.T) {
-	n := test.NewNotifier()
-
+func TestRoot(t *testing.T) {
 	at := &asterTest{
 		src: cli.Dedent(`
-			aster.watch(/.+\.go$/, function() {
-				aster.notify("success", "title", "text");
+			aster.ignore.push(/^(build|proto)$/);
+			aster.root('proto');
+			aster.watch(/.+\.proto$/, function(files) {
+				cycles.push(files);
 			});
 		`),
-		notifier: n,
+		before: func(a *aster.Aster, _ context.CancelFunc) {
+			sh.Mkdir("proto", "build")
+			a.Eval(`var cycles = [];`)
+		},
 		test: func(d time.Duration, _ context.CancelFunc) {
-			sh.Touch("a.go")
+			sh.Touch("proto", "a.proto")
+			sh.Touch("proto", "build", "b.proto")
 			time.Sleep(d)
 		},
+		after: func(a *aster.Aster, w *aster.Watcher) {
+			if g, e := w.Paths(), []string{".", "proto"}; !reflect.DeepEqual(g, e) {
+				t.Fatalf("expected %v, got %v", e, g)
+			}
+			v, _ := a.Eval(`cycles.join(';');`)
+			if g, e := v.String(), filepath.Join("proto", "a.proto"); g != e {
+				t.Fatalf("expected %q, got %q", e, g)
+			}
+		},
 	}
 	stderr, err := at.Run()
 	if err != nil {
@@ -122,63 +993,158 @@ func TestNotify(t *testing.T) {
 	}
 }
 
-func TestNotifyError(t *testing.T) {
-	n := test.NewNotifier()
+func TestRootValue(t *testing.T) {
+	var v aster.RootValue
+	for _, s := range []string{"a", "b"} {
+		v.Set(s)
+	}
+	if g, e := v.Get(), []string{"a", "b"}; !reflect.DeepEqual(g, e) {
+		t.Errorf("RootValue.Get() = %v, expected %v", g, e)
+	}
+	if g, e := v.String(), "a"+string(os.PathListSeparator)+"b"; g != e {
+		t.Errorf("RootValue.String() = %q, expected %q", g, e)
+	}
+
+	v.Set("")
+	if g, e := len(v), 0; g != e {
+		t.Errorf("len(RootValue) = %v, expected %v", g, e)
+	}
+}
+
+func TestRunAll(t *testing.T) {
 	at := &asterTest{
 		src: cli.Dedent(`
-			aster.watch(/.+\.go$/, function() {
-				aster.notify("success", "title", "text");
+			aster.runAll();
+			aster.watch(/.+\.go$/, function(files) {
+			  cycles.push(files.sort());
+			}, { runAll: function(files) {
+			  all.push(files.sort());
+			} });
+			aster.watch(/.+\.md$/, function(files) {
+			  md.push(files.sort());
 			});
+			aster.watch(/^trigger$/, function() {
+			  aster.runAll();
+			}, { runAll: function() {} });
 		`),
-		notifier: n,
-		test: func(d time.Duration, _ context.CancelFunc) {
+		before: func(a *aster.Aster, _ context.CancelFunc) {
+			sh.Mkdir(".git")
+			sh.Mkdir("pkg")
+			sh.Touch(".git", "a.go")
 			sh.Touch("a.go")
+			sh.Touch("pkg", "b.go")
+			sh.Touch("README.md")
+			a.Eval(`var cycles = [], all = [], md = [];`)
+		},
+		test: func(d time.Duration, _ context.CancelFunc) {
 			time.Sleep(d)
+			sh.Touch("trigger")
+			time.Sleep(d)
+		},
+		after: func(a *aster.Aster, _ *aster.Watcher) {
+			v, _ := a.Eval(`[cycles.join(';'), all.join(';'), md.join(';')].join('|');`)
+			files := "a.go," + filepath.Join("pkg", "b.go")
+			if g, e := v.String(), "|"+files+";"+files+"|README.md;README.md"; g != e {
+				t.Errorf("expected %q, got %q", e, g)
+			}
 		},
 	}
-
-	e := fmt.Errorf("Notify error")
-	n.MockCall("Notify", e)
 	stderr, err := at.Run()
 	if err != nil {
 		t.Fatal(err)
 	}
-	lines := strings.SplitN(stderr, "\n", 2)
-	if g, e := lines[0], fmt.Sprintf("aster.test: %v", e); g != e {
+	if g, e := stderr, ""; g != e {
 		t.Errorf("expected %q, got %q", e, g)
 	}
 }
 
-func TestReload(t *testing.T) {
+func TestRunAllReload(t *testing.T) {
+	var b bytes.Buffer
+	src := cli.Dedent(`
+		aster.runAll();
+		aster.watch(/.+\.go$/, function() {});
+	`)
 	at := &asterTest{
-		src: ``,
+		src:     src,
+		options: []aster.Option{aster.JSONLog(&b)},
+		before: func(*aster.Aster, context.CancelFunc) {
+			sh.Touch("a.go")
+		},
 		test: func(d time.Duration, _ context.CancelFunc) {
-			if err := sh.Mkdir("build"); err != nil {
-				t.Fatal(err)
-			}
 			time.Sleep(d)
-
-			sh.Touch("a.go")
-
-			src := `aster.ignore.push(/^build$/);`
 			if err := test.Gen(src); err != nil {
 				t.Fatal(err)
 			}
 			time.Sleep(d)
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stderr, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+	if g, e := strings.Count(b.String(), `"type":"run-all"`), 1; g != e {
+		t.Errorf("expected %v, got %v", e, g)
+	}
+}
 
-			src = `++;`
-			if err := test.Gen(src); err != nil {
-				t.Fatal(err)
+func TestSymlink(t *testing.T) {
+	dir1 := t.TempDir()
+	dir2 := t.TempDir()
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.followSymlinks = true;
+			aster.watch(/.+\.go$/, function(files) {
+			  cycles.push(files.sort());
+			});
+		`),
+		before: func(a *aster.Aster, _ context.CancelFunc) {
+			sh.Mkdir(dir1, "pkg")
+			sh.Mkdir("src")
+			if err := os.Symlink(dir1, filepath.Join("src", "ext")); err != nil {
+				t.Skip(err)
+			}
+			// loops
+			wd, _ := os.Getwd()
+			os.Symlink("..", filepath.Join("src", "parent"))
+			os.Symlink(filepath.Join(wd, "src"), filepath.Join(dir1, "src"))
+			sh.Touch(dir2, "a.go")
+			a.Eval(`var cycles = [];`)
+		},
+		test: func(d time.Duration, _ context.CancelFunc) {
+			sh.Touch(dir1, "pkg", "b.go")
+			time.Sleep(d)
+			// new symlink
+			if err := os.Symlink(dir2, "ext"); err != nil {
+				t.Error(err)
 			}
 			time.Sleep(d)
+			sh.Touch(dir2, "c.go")
+			time.Sleep(d)
+		},
+		after: func(a *aster.Aster, w *aster.Watcher) {
+			if g, e := w.Paths(), []string{
+				".",
+				"ext",
+				"src",
+				filepath.Join("src", "ext"),
+				filepath.Join("src", "ext", "pkg"),
+			}; !reflect.DeepEqual(g, e) {
+				t.Errorf("expected %v, got %v", e, g)
+			}
+			v, _ := a.Eval(`cycles.join(';');`)
+			if g, e := v.String(), filepath.Join("src", "ext", "pkg", "b.go")+";"+filepath.Join("ext", "a.go")+";"+filepath.Join("ext", "c.go"); g != e {
+				t.Errorf("expected %q, got %q", e, g)
+			}
 		},
 	}
 	stderr, err := at.Run()
 	if err != nil {
 		t.Fatal(err)
 	}
-	lines := strings.SplitN(stderr, "\n", 2)
-	if g, e := lines[0], "aster.test: failed to reload"; g != e {
+	if g, e := stderr, ""; g != e {
 		t.Errorf("expected %q, got %q", e, g)
 	}
 }
@@ -265,6 +1231,275 @@ func TestWatch(t *testing.T) {
 	}
 }
 
+func TestWatchSchedule(t *testing.T) {
+	var a *aster.Aster
+	count := func(name string) string {
+		v, _ := a.Eval(name + `.length;`)
+		return v.String()
+	}
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.watch(/.+\.md$/, function(files) {
+			  md.push(files.sort());
+			}, { leading: true });
+			aster.watch(/.+\.txt$/, function(files) {
+			  txt.push(files.sort());
+			}, { debounce: 20, throttle: '400ms' });
+			aster.watch(/.+\.go$/, function(files) {
+			  go.push(files.sort());
+			});
+			aster.watch(/.+\.c$/, function(files) {
+			  c.push(files.sort());
+			}, { debounce: 100 });
+			aster.watch(/.+\.h$/, function(files) {
+			  h.push(files.sort());
+			}, { debounce: 100 });
+			aster.watch(/^\u0041\.js$/, function() {});
+		`),
+		before: func(aa *aster.Aster, _ context.CancelFunc) {
+			a = aa
+			a.Eval(`var md = [], txt = [], go = [], c = [], h = [];`)
+		},
+		test: func(d time.Duration, _ context.CancelFunc) {
+			// leading
+			sh.Touch("a.md")
+			sh.Touch("a.go")
+			time.Sleep(d / 4)
+			if g, e := count("md"), "1"; g != e {
+				t.Errorf("expected %v, got %v", e, g)
+			}
+			if g, e := count("go"), "0"; g != e {
+				t.Errorf("expected %v, got %v", e, g)
+			}
+			time.Sleep(d)
+			if g, e := count("go"), "1"; g != e {
+				t.Errorf("expected %v, got %v", e, g)
+			}
+			// throttle
+			sh.Touch("a.txt")
+			time.Sleep(d / 2)
+			sh.Touch("b.txt")
+			time.Sleep(d / 2)
+			if g, e := count("txt"), "1"; g != e {
+				t.Errorf("expected %v, got %v", e, g)
+			}
+			time.Sleep(d * 2)
+			if g, e := count("txt"), "2"; g != e {
+				t.Errorf("expected %v, got %v", e, g)
+			}
+			// timer for each watch
+			sh.Touch("a.c")
+			time.Sleep(d * 3 / 8)
+			sh.Touch("a.h")
+			time.Sleep(d * 3 / 8)
+			if g, e := count("c"), "1"; g != e {
+				t.Errorf("expected %v, got %v", e, g)
+			}
+			if g, e := count("h"), "0"; g != e {
+				t.Errorf("expected %v, got %v", e, g)
+			}
+			time.Sleep(d)
+		},
+		after: func(a *aster.Aster, _ *aster.Watcher) {
+			v, _ := a.Eval(`[md.join(';'), txt.join(';'), go.join(';'), c.join(';'), h.join(';')].join('|');`)
+			if g, e := v.String(), "a.md|a.txt;b.txt|a.go|a.c|a.h"; g != e {
+				t.Errorf("expected %q, got %q", e, g)
+			}
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stderr, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
+func TestWatchDirs(t *testing.T) {
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.ignore.push(/^build$/);
+			aster.watch(/^(new|old)$/, function(files) {
+				dirs.push(files.sort());
+			}, { dirs: true });
+			aster.watch(/.+\.go$/, function(files) {
+				cycles.push(files.sort());
+			});
+		`),
+		before: func(a *aster.Aster, _ context.CancelFunc) {
+			sh.Mkdir("old")
+			sh.Mkdir("build", "new", "pkg")
+			sh.Touch("build", "new", "a.go")
+			sh.Touch("build", "new", "pkg", "b.go")
+			a.Eval(`var dirs = [], cycles = [];`)
+		},
+		test: func(d time.Duration, _ context.CancelFunc) {
+			os.RemoveAll("old")
+			os.Rename(filepath.Join("build", "new"), "new")
+			time.Sleep(d)
+		},
+		after: func(a *aster.Aster, _ *aster.Watcher) {
+			sep := string(os.PathSeparator)
+			v, _ := a.Eval(`dirs.join(';');`)
+			if g, e := v.String(), "new"+sep+",old"+sep; g != e {
+				t.Errorf("expected %q, got %q", e, g)
+			}
+			v, _ = a.Eval(`cycles.join(';');`)
+			if g, e := v.String(), filepath.Join("new", "a.go")+","+filepath.Join("new", "pkg", "b.go"); g != e {
+				t.Errorf("expected %q, got %q", e, g)
+			}
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stderr, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
+func TestWatchNoDirs(t *testing.T) {
+	var b bytes.Buffer
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.watch(/.+\.go$/, function() {});
+		`),
+		options: []aster.Option{aster.Log(slog.New(slog.NewTextHandler(&b, nil)))},
+		before: func(*aster.Aster, context.CancelFunc) {
+			sh.Mkdir("old")
+		},
+		test: func(d time.Duration, _ context.CancelFunc) {
+			sh.Mkdir("new")
+			os.RemoveAll("old")
+			time.Sleep(d)
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stderr, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+	if strings.Contains(b.String(), "msg=batch") {
+		t.Errorf("unexpected batch: %q", b.String())
+	}
+}
+
+func TestWatchRename(t *testing.T) {
+	var b bytes.Buffer
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.watch(/.+\.go$/, function(files) {
+			  files.forEach(function(f) {
+			    renames.push(typeof f === 'string' ? f : f.op + ':' + f.from + '>' + f.to);
+			  });
+			}, { renames: true });
+			aster.watch(/.+\.txt$/, function(files) {
+			  cycles.push(files.sort());
+			});
+		`),
+		options: []aster.Option{aster.JSONLog(&b)},
+		before: func(a *aster.Aster, _ context.CancelFunc) {
+			sh.Touch("a.go")
+			sh.Touch("b.go")
+			a.Eval(`var renames = [], cycles = [];`)
+		},
+		test: func(d time.Duration, _ context.CancelFunc) {
+			os.Rename("a.go", "c.go")
+			os.Rename("b.go", "b.txt")
+			time.Sleep(d)
+		},
+		after: func(a *aster.Aster, _ *aster.Watcher) {
+			v, _ := a.Eval(`renames.sort().join(';');`)
+			if g, e := v.String(), "rename:a.go>c.go;rename:b.go>b.txt"; g != e {
+				t.Errorf("expected %q, got %q", e, g)
+			}
+			v, _ = a.Eval(`cycles.join(';');`)
+			if g, e := v.String(), ""; g != e {
+				t.Errorf("expected %q, got %q", e, g)
+			}
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stderr, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+
+	var r aster.Record
+	if err := json.Unmarshal(b.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+	renames := []*aster.RenameRecord{
+		{From: "b.go", To: "b.txt"},
+		{From: "a.go", To: "c.go"},
+	}
+	if g, e := r.Files, []string{"b.txt", "c.go"}; !reflect.DeepEqual(g, e) {
+		t.Errorf("expected %v, got %v", e, g)
+	}
+	if g, e := r.Renames, renames; !reflect.DeepEqual(g, e) {
+		t.Errorf("expected %v, got %v", e, g)
+	}
+	if len(r.Watches) != 1 {
+		t.Fatalf("unexpected watches: %v", r.Watches)
+	}
+	slices.SortFunc(r.Watches[0].Renames, func(a, b *aster.RenameRecord) int {
+		return strings.Compare(a.To, b.To)
+	})
+	if g, e := r.Watches[0].Renames, renames; !reflect.DeepEqual(g, e) {
+		t.Errorf("expected %v, got %v", e, g)
+	}
+}
+
+func TestWatchRenameOut(t *testing.T) {
+	dir := t.TempDir()
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.watch(/.+\.go$/, function(files) {
+			  files.forEach(function(f) {
+			    renames.push(typeof f === 'string' ? f : f.op + ':' + f.from + '>' + f.to);
+			  });
+			}, { renames: true });
+		`),
+		before: func(a *aster.Aster, _ context.CancelFunc) {
+			for _, n := range []string{"a.go", "c.go", "x.go"} {
+				sh.Touch(n)
+			}
+			a.Eval(`var renames = [];`)
+		},
+		test: func(d time.Duration, _ context.CancelFunc) {
+			// after the batch
+			os.Rename("a.go", filepath.Join(dir, "a.go"))
+			time.Sleep(d)
+			sh.Touch("b.go")
+			time.Sleep(d)
+			// not in a row
+			os.Rename("c.go", filepath.Join(dir, "c.go"))
+			os.WriteFile("x.go", []byte("package x\n"), 0o666)
+			sh.Touch("d.go")
+			time.Sleep(d)
+		},
+		after: func(a *aster.Aster, _ *aster.Watcher) {
+			v, _ := a.Eval(`renames.sort().join(';');`)
+			if g, e := v.String(), "b.go;d.go;x.go"; g != e {
+				t.Errorf("expected %q, got %q", e, g)
+			}
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stderr, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
+
 func TestWatchError(t *testing.T) {
 	at := &asterTest{
 		src: cli.Dedent(`
@@ -287,10 +1522,58 @@ func TestWatchError(t *testing.T) {
 	}
 }
 
+func BenchmarkRemove(b *testing.B) {
+	for _, n := range []int{100, 1000, 4000} {
+		b.Run(fmt.Sprintf("dirs=%v", n), func(b *testing.B) {
+			err := test.Sandbox(func() error {
+				for i := range n {
+					if err := sh.Mkdir("tree", strconv.Itoa(i)); err != nil {
+						return err
+					}
+				}
+				if err := sh.Mkdir("sub", "a", "b"); err != nil {
+					return err
+				}
+				if err := test.Gen(``); err != nil {
+					return err
+				}
+				a, err := test.New()
+				if err != nil {
+					return err
+				}
+				w, err := aster.NewWatcher(context.Background(), a)
+				if err != nil {
+					return err
+				}
+				defer w.Close()
+				go w.Watch()
+
+				b.ResetTimer()
+				for range b.N {
+					if err := w.Remove("sub"); err != nil {
+						return err
+					}
+					b.StopTimer()
+					if err := w.Add("sub"); err != nil {
+						return err
+					}
+					b.StartTimer()
+				}
+				return nil
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		})
+	}
+}
+
 type asterTest struct {
 	src      string
 	notifier notify.Notifier
+	options  []aster.Option
 	before   func(*aster.Aster, context.CancelFunc)
+	watch    func(*aster.Watcher)
 	test     func(time.Duration, context.CancelFunc)
 	after    func(*aster.Aster, *aster.Watcher)
 }
@@ -306,7 +1589,7 @@ func (t *asterTest) Run() (string, error) {
 			if err := test.Gen(t.src); err != nil {
 				return err
 			}
-			a, err := aster.New(app, t.notifier)
+			a, err := aster.New(app, t.notifier, t.options...)
 			if err != nil {
 				return err
 			}
@@ -325,6 +1608,9 @@ func (t *asterTest) Run() (string, error) {
 			defer w.Close()
 
 			w.Squash = s
+			if t.watch != nil {
+				t.watch(w)
+			}
 			go w.Watch()
 			t.test(time.Duration(s.Nanoseconds()*2), cancel)
 
@@ -1,7 +1,7 @@
 //
 // aster :: asterfile.go
 //
-//   Copyright (c) 2014-2025 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -11,12 +11,18 @@ package aster
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
 	"regexp"
 	"runtime"
-	"strconv"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/hattya/go.cli"
 	"github.com/hattya/go.notify"
@@ -34,6 +40,7 @@ func init() {
 	}
 	b.WriteString(`])(?:`)
 	for i, s := range []string{
+		".aster",
 		".bzr",
 		".git",
 		".hg",
@@ -49,21 +56,119 @@ func init() {
 	defaultIgnore = b.String()
 }
 
+// editor temp files
+var tempFiles = []string{
+	`/^\..+\.sw[a-p]$/`,       // Vim
+	`/^4913$/`,                // Vim
+	`/~$/`,                    // Vim, Emacs
+	`/^\.#/`,                  // Emacs
+	`/^#.*#$/`,                // Emacs
+	`/^\.goutputstream-/`,     // GNOME
+	`/\.kate-swp$/`,           // Kate
+	`/___jb_(?:old|tmp)___$/`, // JetBrains
+	`/^\.DS_Store$/`,          // macOS
+}
+
 type Aster struct {
-	ui *cli.CLI
-	i  int32
-	n  notify.Notifier
+	ui     *cli.CLI
+	file   string
+	i      int32
+	all    int32
+	n      notify.Notifier
+	roots  []string
+	term   Terminal
+	links  bool
+	dry    bool
+	quiet  bool // discard dry-run messages
+	detach bool
+	log    *slog.Logger
+	jl     io.Writer // JSON log
+	vcs    atomic.Bool
+	follow atomic.Bool
+	paused atomic.Bool
+	drop   atomic.Bool
+	drops  int32
+	rx     struct {
+		ignore atomic.Pointer[patterns]
+		temp   atomic.Pointer[patterns]
+	}
+	ws    atomic.Pointer[[]*watch]
+	procs procs
+
+	mu       sync.Mutex
+	vm       *module.Otto
+	watches  []*watch
+	dirs     []string
+	loaded   bool         // Asterfile has been evaluated
+	top      bool         // evaluating the top level of Asterfile
+	rec      *WatchRecord // current watch
+	cur      *watch       // running watch
+	problems []string
+	run      bool // counting failures for Run
+	failures int
+}
+
+type Option func(*Aster)
+
+func Roots(roots ...string) Option {
+	return func(a *Aster) {
+		a.roots = append(a.roots, roots...)
+	}
+}
+
+// Terminal is the interface that hands over the terminal to child processes.
+type Terminal interface {
+	Suspend()
+	Resume()
+}
 
-	mu      sync.Mutex
-	vm      *module.Otto
-	watches []*watch
+func Term(t Terminal) Option {
+	return func(a *Aster) {
+		a.term = t
+	}
+}
+
+func FollowSymlinks(follow bool) Option {
+	return func(a *Aster) {
+		a.links = follow
+	}
 }
 
-func New(ui *cli.CLI, n notify.Notifier) (*Aster, error) {
+// DryRun logs operations of the os module instead of performing them.
+func DryRun(dry bool) Option {
+	return func(a *Aster) {
+		a.dry = dry
+	}
+}
+
+// DetachStdin does not connect the standard input to child processes.
+func DetachStdin(detach bool) Option {
+	return func(a *Aster) {
+		a.detach = detach
+	}
+}
+
+// Log traces events, ignore decisions and matches by l.
+func Log(l *slog.Logger) Option {
+	return func(a *Aster) {
+		a.log = l
+	}
+}
+
+func New(ui *cli.CLI, n notify.Notifier, opts ...Option) (*Aster, error) {
 	a := &Aster{
 		ui: ui,
 		n:  n,
 	}
+	for _, o := range opts {
+		o(a)
+	}
+	if a.file == "" {
+		a.file = "Asterfile"
+	}
+	if a.log == nil {
+		a.log = slog.New(discardHandler{})
+	}
 	if err := a.eval(); err != nil {
 		return nil, err
 	}
@@ -71,29 +176,82 @@ func New(ui *cli.CLI, n notify.Notifier) (*Aster, error) {
 }
 
 func (a *Aster) eval() error {
-	a.vm = newVM()
+	a.vm = newVM(a)
 	a.watches = nil
+	a.dirs = nil
+	a.problems = nil
 	// aster object
 	aster, _ := a.vm.Object(fmt.Sprintf(`
 		aster = {
 		  arch: %q,
+		  dropOnResume: false,
+		  followSymlinks: %v,
 		  ignore: [/%v/],
+		  ignoreVCS: false,
+		  loopLimit: 3,
 		  os: %q,
+		  tempFiles: [%v],
 		}
-	`, runtime.GOARCH, defaultIgnore, runtime.GOOS))
+	`, runtime.GOARCH, a.links, defaultIgnore, runtime.GOOS, strings.Join(tempFiles, ", ")))
 	aster.Set("notify", a.notify)
+	aster.Set("pause", a.pause)
+	aster.Set("resume", a.resume)
+	aster.Set("root", a.root)
+	aster.Set("runAll", a.runAll)
 	aster.Set("title", a.title)
 	aster.Set("watch", a.watch)
 	// watch Asterfile
 	rx, _ := a.vm.Call(`new RegExp`, nil, `^Asterfile$`)
 	aster.Call("watch", rx, a.reload)
+	a.watches[len(a.watches)-1].builtin = true
 	// eval Asterfile
-	script, err := a.vm.Compile("Asterfile", nil)
+	script, err := a.vm.Compile(a.file, nil)
 	if err != nil {
 		return module.Wrap(err)
 	}
+	a.top = true
 	_, err = a.vm.Run(script)
-	return module.Wrap(err)
+	a.top = false
+	if err != nil {
+		return module.Wrap(err)
+	}
+	a.loaded = true
+	v, _ := aster.Get("ignoreVCS")
+	vcs, _ := v.ToBoolean()
+	a.vcs.Store(vcs)
+	v, _ = aster.Get("followSymlinks")
+	follow, _ := v.ToBoolean()
+	a.follow.Store(follow)
+	a.compile()
+	return nil
+}
+
+// compile translates aster.ignore and aster.tempFiles into Go when they have
+// been changed. It also loads aster.dropOnResume.
+func (a *Aster) compile() {
+	for _, rx := range []struct {
+		src string
+		p   *atomic.Pointer[patterns]
+	}{
+		{`aster.ignore`, &a.rx.ignore},
+		{`aster.tempFiles`, &a.rx.temp},
+	} {
+		ary, _ := a.vm.Object(rx.src)
+		if p := newPatterns(ary); !p.equal(rx.p.Load()) {
+			rx.p.Store(p)
+		}
+	}
+	// snapshot of watches for scheduling
+	ws := slices.Clone(a.watches)
+	a.ws.Store(&ws)
+	a.dropOnResume()
+}
+
+// dropOnResume loads aster.dropOnResume which can be changed at any time.
+func (a *Aster) dropOnResume() {
+	v, _ := a.vm.Run(`aster.dropOnResume`)
+	drop, _ := v.ToBoolean()
+	a.drop.Store(drop)
 }
 
 func (a *Aster) watch(call otto.FunctionCall) otto.Value {
@@ -101,17 +259,64 @@ func (a *Aster) watch(call otto.FunctionCall) otto.Value {
 	if rx.Class() == "RegExp" {
 		fn := call.Argument(1)
 		if fn.Class() == "Function" {
-			a.watches = append(a.watches, &watch{
-				rx: rx.Object(),
-				fn: fn.Object(),
-			})
+			w := &watch{
+				rx:  rx.Object(),
+				fn:  fn.Object(),
+				loc: location(call),
+			}
+			w.re = translate(regexpOf(w.rx))
+			if v := call.Argument(2); v.IsObject() {
+				options := v.Object()
+				// dirs
+				v, _ = options.Get("dirs")
+				w.dirs, _ = v.ToBoolean()
+				// renames
+				v, _ = options.Get("renames")
+				w.renames, _ = v.ToBoolean()
+				// scheduling
+				v, _ = options.Get("debounce")
+				w.debounce = toDuration(v)
+				v, _ = options.Get("throttle")
+				w.throttle = toDuration(v)
+				v, _ = options.Get("leading")
+				w.leading, _ = v.ToBoolean()
+				// runAll
+				if v, _ = options.Get("runAll"); v.Class() == "Function" {
+					w.all = v.Object()
+				}
+				// outputs
+				v, _ = options.Get("outputs")
+				w.outputs = outputsOf(call.Otto, v)
+			}
+			a.watches = append(a.watches, w)
+		} else {
+			a.problem(call, "aster.watch: callback is not a Function")
 		}
+	} else {
+		a.problem(call, "aster.watch: pattern is not a RegExp")
 	}
 	return otto.UndefinedValue()
 }
 
+// toDuration converts a Number in milliseconds or a String into
+// time.Duration.
+func toDuration(v otto.Value) time.Duration {
+	switch {
+	case v.IsNumber():
+		f, _ := v.ToFloat()
+		if f > 0 {
+			return time.Duration(f * float64(time.Millisecond))
+		}
+	case v.IsString():
+		if d, err := time.ParseDuration(v.String()); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
 func (a *Aster) notify(call otto.FunctionCall) otto.Value {
-	if a.n == nil || len(call.ArgumentList) < 3 {
+	if len(call.ArgumentList) < 3 {
 		return otto.UndefinedValue()
 	}
 
@@ -119,9 +324,46 @@ func (a *Aster) notify(call otto.FunctionCall) otto.Value {
 	for i := range args {
 		args[i], _ = call.ArgumentList[i].ToString()
 	}
-	if err := a.n.Notify(args[0], args[1], args[2]); err != nil {
-		warn(a.ui, err)
+	if args[0] == "failure" {
+		a.fail()
+	}
+	var err error
+	if a.n != nil {
+		if err = a.n.Notify(args[0], args[1], args[2]); err != nil {
+			warn(a.ui, err)
+		}
+	}
+	a.rec.notify(args, err)
+	return otto.UndefinedValue()
+}
+
+func (a *Aster) pause(otto.FunctionCall) otto.Value {
+	a.Pause()
+	return otto.UndefinedValue()
+}
+
+func (a *Aster) resume(otto.FunctionCall) otto.Value {
+	a.dropOnResume()
+	a.Resume()
+	return otto.UndefinedValue()
+}
+
+func (a *Aster) root(call otto.FunctionCall) otto.Value {
+	if len(call.ArgumentList) < 1 {
+		return otto.UndefinedValue()
 	}
+
+	dir, _ := call.ArgumentList[0].ToString()
+	a.dirs = append(a.dirs, dir)
+	return otto.UndefinedValue()
+}
+
+func (a *Aster) runAll(otto.FunctionCall) otto.Value {
+	if a.top && a.loaded {
+		// reloaded
+		return otto.UndefinedValue()
+	}
+	atomic.StoreInt32(&a.all, 1)
 	return otto.UndefinedValue()
 }
 
@@ -139,6 +381,8 @@ func (a *Aster) reload(otto.FunctionCall) otto.Value {
 	// create snapshot
 	vm := a.vm
 	watches := a.watches
+	dirs := a.dirs
+	problems := a.problems
 	// eval
 	var name, text string
 	if err := a.eval(); err != nil {
@@ -147,6 +391,8 @@ func (a *Aster) reload(otto.FunctionCall) otto.Value {
 		// rollback to snapshot
 		a.vm = vm
 		a.watches = watches
+		a.dirs = dirs
+		a.problems = problems
 
 		name = "failure"
 		text = "Error occurred while reloading Asterfile"
@@ -162,43 +408,195 @@ func (a *Aster) reload(otto.FunctionCall) otto.Value {
 	return v
 }
 
+func (a *Aster) Reload() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.compile()
+
+	a.reload(otto.FunctionCall{})
+}
+
 func (a *Aster) Eval(src any) (otto.Value, error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
+	defer a.compile()
 
 	return a.vm.Run(src)
 }
 
 func (a *Aster) Ignore(name string) bool {
+	if rx := a.test(a.rx.ignore.Load(), name); rx != "" {
+		a.log.Debug("ignore", "name", name, "pattern", rx)
+		return true
+	}
+	return false
+}
+
+func (a *Aster) TempFile(name string) bool {
+	if rx := a.test(a.rx.temp.Load(), filepath.Base(name)); rx != "" {
+		a.log.Debug("ignore", "name", name, "pattern", rx)
+		return true
+	}
+	return false
+}
+
+// test returns the pattern which matches name.
+func (a *Aster) test(p *patterns, name string) string {
+	if p == nil {
+		return ""
+	}
+	for i, rx := range p.rx {
+		if rx.MatchString(name) {
+			return p.src[i]
+		}
+	}
+	if len(p.js) > 0 {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+
+		for _, o := range p.js {
+			v, _ := o.Call("test", name)
+			if b, _ := v.ToBoolean(); b {
+				return o.Value().String()
+			}
+		}
+	}
+	return ""
+}
+
+func (a *Aster) Roots() []string {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	ary, _ := a.vm.Object(`aster.ignore`)
-	if ary.Class() == "Array" {
-		// aster.ignore.length
-		v, _ := ary.Get("length")
-		n, _ := v.ToInteger()
-
-		for i := range n {
-			v, _ := ary.Get(strconv.FormatInt(i, 10))
-			if v.Class() == "RegExp" {
-				v, _ = v.Object().Call("test", name)
-				if b, _ := v.ToBoolean(); b {
-					return true
-				}
+	roots := []string{"."}
+	seen := map[string]bool{".": true}
+	for _, l := range [][]string{a.roots, a.dirs} {
+		for _, s := range l {
+			if s = filepath.Clean(s); !seen[s] {
+				seen[s] = true
+				roots = append(roots, s)
 			}
 		}
 	}
-	return false
+	return roots
+}
+
+func (a *Aster) FollowSymlinks() bool {
+	return a.follow.Load()
+}
+
+// receiver returns the watch which will receive name, to schedule name by
+// its options. It returns nil if it cannot be determined without the VM.
+func (a *Aster) receiver(name, from string) *watch {
+	ws := a.ws.Load()
+	if ws == nil {
+		return nil
+	}
+	s, dir := strings.CutSuffix(name, string(os.PathSeparator))
+	for _, w := range *ws {
+		switch {
+		case dir && !w.dirs:
+		case w.re == nil:
+			// untranslatable RegExp
+			return nil
+		case w.re.MatchString(s) || (w.renames && from != "" && w.re.MatchString(from)):
+			return w
+		}
+	}
+	return nil
+}
+
+// current reports whether w is defined by the current Asterfile.
+func (a *Aster) current(w *watch) bool {
+	ws := a.ws.Load()
+	return w == nil || (ws != nil && slices.Contains(*ws, w))
+}
+
+// watchDirs reports whether any watch receives directories.
+func (a *Aster) watchDirs() bool {
+	ws := a.ws.Load()
+	return ws != nil && slices.ContainsFunc(*ws, func(w *watch) bool {
+		return w.dirs
+	})
+}
+
+// numWatches returns the number of watches defined in the Asterfile.
+func (a *Aster) numWatches() int {
+	ws := a.ws.Load()
+	if ws == nil {
+		return 0
+	}
+	return len(slices.DeleteFunc(slices.Clone(*ws), func(w *watch) bool {
+		return w.builtin
+	}))
+}
+
+func (a *Aster) IgnoreVCS() bool {
+	return a.vcs.Load()
 }
 
 func (a *Aster) Reloaded() bool {
 	return atomic.SwapInt32(&a.i, 0) > 0
 }
 
-func (a *Aster) OnChange(ctx context.Context, files map[string]int) {
+func (a *Aster) RunAllRequested() bool {
+	return atomic.SwapInt32(&a.all, 0) > 0
+}
+
+// Terminate terminates the running child processes, and prevents starting
+// new ones.
+func (a *Aster) Terminate() {
+	a.procs.terminate()
+}
+
+// Pause suspends invoking callbacks.
+func (a *Aster) Pause() {
+	a.paused.Store(true)
+}
+
+// Resume resumes invoking callbacks. It reports whether it was paused.
+func (a *Aster) Resume() bool {
+	if !a.paused.Swap(false) {
+		return false
+	}
+	if a.drop.Load() {
+		atomic.StoreInt32(&a.drops, 1)
+	}
+	return true
+}
+
+func (a *Aster) Paused() bool {
+	return a.paused.Load()
+}
+
+// DropRequested reports whether the events during the pause should be
+// dropped.
+func (a *Aster) DropRequested() bool {
+	return atomic.SwapInt32(&a.drops, 0) > 0
+}
+
+func (a *Aster) OnChange(ctx context.Context, files map[string]int, renames map[string]string) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
+	defer a.compile()
+
+	var batch []string
+	if a.dry || a.log.Enabled(ctx, slog.LevelInfo) {
+		batch = make([]string, 0, len(files))
+		for n := range files {
+			if from, ok := renames[n]; ok {
+				n = from + " -> " + n
+			}
+			batch = append(batch, n)
+		}
+		slices.Sort(batch)
+	}
+	a.log.Info("batch", "files", batch)
+	if a.dry {
+		a.dryRun("batch: %v", strings.Join(batch, ", "))
+	}
+	r := a.begin("change", maps.Keys(files), renames)
+	defer a.end(r)
 
 	i := atomic.LoadInt32(&a.i)
 L:
@@ -211,19 +609,39 @@ L:
 		// call RegExp.test
 		var cl []any
 		for n := range files {
-			v, _ := w.rx.Call("test", n)
-			if b, _ := v.ToBoolean(); b {
-				cl = append(cl, n)
+			s, dir := strings.CutSuffix(n, string(os.PathSeparator))
+			if dir && !w.dirs {
+				continue
+			}
+			// written by the previous callback of w
+			if w.wrote(n) {
+				a.log.Debug("drop", "name", n, "reason", "output", "watch", w.rx.Value().String())
+				continue
+			}
+			from, renamed := renames[n]
+			v, _ := w.rx.Call("test", s)
+			b, _ := v.ToBoolean()
+			if !b && renamed && w.renames {
+				v, _ = w.rx.Call("test", from)
+				b, _ = v.ToBoolean()
+			}
+			if b {
+				if renamed && w.renames {
+					o, _ := a.vm.Object(`({op: 'rename'})`)
+					o.Set("from", from)
+					o.Set("to", n)
+					cl = append(cl, o)
+				} else {
+					cl = append(cl, n)
+				}
 				delete(files, n)
+				delete(renames, n)
 			}
 		}
 		// call Function.call
 		if len(cl) > 0 {
-			ary, _ := a.vm.Call(`new Array`, nil, cl...)
-			_, err := w.fn.Call("call", nil, ary)
-			if err != nil {
-				warn(a.ui, module.Wrap(err))
-			}
+			a.loop(w, w.triggered(cl))
+			a.call(r, w, w.fn, cl)
 		}
 
 		if len(files) == 0 {
@@ -236,7 +654,119 @@ L:
 	}
 }
 
+// RunAll invokes each watch once with all of the matching files.
+func (a *Aster) RunAll(ctx context.Context, files []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	defer a.compile()
+
+	files = slices.Clone(files)
+	a.log.Info("run all", "files", len(files))
+	if a.dry {
+		a.dryRun("run all: %v files", len(files))
+	}
+	r := a.begin("run-all", slices.Values(files), nil)
+	defer a.end(r)
+	for _, w := range a.watches {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		// call RegExp.test
+		var cl []any
+		files = slices.DeleteFunc(files, func(n string) bool {
+			v, _ := w.rx.Call("test", n)
+			b, _ := v.ToBoolean()
+			if b {
+				cl = append(cl, n)
+			}
+			return b
+		})
+		if w.builtin || len(cl) == 0 {
+			continue
+		}
+		// call Function.call
+		fn := w.fn
+		if w.all != nil {
+			fn = w.all
+		}
+		a.call(r, w, fn, cl)
+	}
+}
+
+// call invokes fn of w with cl.
+func (a *Aster) call(r *Record, w *watch, fn *otto.Object, cl []any) {
+	names := make([]string, len(cl))
+	files := make([]string, len(cl))
+	var renames []*RenameRecord
+	for i, v := range cl {
+		if o, ok := v.(*otto.Object); ok {
+			from, _ := o.Get("from")
+			to, _ := o.Get("to")
+			names[i] = from.String() + " -> " + to.String()
+			files[i] = to.String()
+			renames = append(renames, &RenameRecord{From: from.String(), To: to.String()})
+		} else {
+			names[i] = v.(string)
+			files[i] = names[i]
+		}
+	}
+	rx := w.rx.Value().String()
+	a.log.Info("match", "watch", rx, "files", names)
+	if a.dry {
+		a.dryRun("watch %v: %v", rx, strings.Join(names, ", "))
+	}
+
+	a.rec = r.watch(rx, files, renames)
+	a.cur = w
+	clear(w.written)
+	w.children = nil
+	defer func() {
+		a.rec = nil
+		a.cur = nil
+	}()
+	start := time.Now()
+	ary, _ := a.vm.Call(`new Array`, nil, cl...)
+	_, err := fn.Call("call", nil, ary)
+	w.start, w.end = start, time.Now()
+	if err != nil {
+		err = module.Wrap(err)
+		warn(a.ui, err)
+		a.fail()
+	}
+	a.rec.done(start, err)
+}
+
+// fail counts a failure while Run is in progress.
+func (a *Aster) fail() {
+	if a.run {
+		a.failures++
+	}
+}
+
+// dryRun logs an operation in the dry-run mode.
+func (a *Aster) dryRun(format string, args ...any) {
+	if a.quiet {
+		return
+	}
+	a.ui.Errorf("%v: dry-run: %v\n", a.ui.Name, fmt.Sprintf(format, args...))
+}
+
 type watch struct {
-	rx *otto.Object // RegExp
-	fn *otto.Object // Function
+	loc      string       // where aster.watch is called
+	rx       *otto.Object // RegExp
+	fn       *otto.Object // Function
+	all      *otto.Object // Function
+	re       *regexp.Regexp
+	builtin  bool
+	dirs     bool
+	renames  bool
+	outputs  *otto.Object // Array of String or RegExp
+	loops    int          // how many times it has triggered itself in a row
+	start    time.Time    // previous invocation
+	end      time.Time
+	written  map[string]bool // written by the previous invocation
+	children []span          // child processes of the previous invocation
+	schedule
 }
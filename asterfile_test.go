@@ -1,7 +1,7 @@
 //
 // aster :: asterfile_test.go
 //
-//   Copyright (c) 2014-2020 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -9,9 +9,12 @@
 package aster_test
 
 import (
+	"reflect"
 	"testing"
 
+	"github.com/hattya/aster"
 	"github.com/hattya/aster/internal/test"
+	"github.com/hattya/go.cli"
 )
 
 func TestNoAsterfile(t *testing.T) {
@@ -40,6 +43,94 @@ func TestError(t *testing.T) {
 	}
 }
 
+func TestTerminal(t *testing.T) {
+	err := test.Sandbox(func() {
+		src := `require('os').system(['go', 'version'], { stdout: null });`
+		if err := test.Gen(src); err != nil {
+			t.Fatal(err)
+		}
+		term := new(terminal)
+		if _, err := aster.New(cli.NewCLI(), nil, aster.Term(term)); err != nil {
+			t.Fatal(err)
+		}
+		if g, e := term.calls, []string{"suspend", "resume"}; !reflect.DeepEqual(g, e) {
+			t.Errorf("expected %v, got %v", e, g)
+		}
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+type terminal struct {
+	calls []string
+}
+
+func (t *terminal) Suspend() { t.calls = append(t.calls, "suspend") }
+func (t *terminal) Resume()  { t.calls = append(t.calls, "resume") }
+
+var ignoreTests = []struct {
+	name    string
+	ignored bool
+}{
+	{".git", true},
+	{"build", true},
+	{"BUILD", true},
+	{"builder", false},
+	{"vendor", true},
+	{"vendored", false},
+	{"tmp", false},
+	{"tmp1", true},
+}
+
+func TestIgnorePatterns(t *testing.T) {
+	err := test.Sandbox(func() {
+		src := cli.Dedent(`
+			aster.ignore.push(/^build$/i);
+			aster.ignore.push(/^\u0076endor$/);
+		`)
+		if err := test.Gen(src); err != nil {
+			t.Fatal(err)
+		}
+		a, err := test.New()
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+		// updated by Aster.Eval
+		a.Eval(`aster.ignore.push(/^tmp\d$/);`)
+
+		for _, tt := range ignoreTests {
+			if g, e := a.Ignore(tt.name), tt.ignored; g != e {
+				t.Errorf("Aster.Ignore(%q) = %v, expected %v", tt.name, g, e)
+			}
+		}
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkIgnore(b *testing.B) {
+	err := test.Sandbox(func() {
+		src := `aster.ignore.push(/^(coverage|node_modules)$/);`
+		if err := test.Gen(src); err != nil {
+			b.Fatal(err)
+		}
+		a, err := test.New()
+		if err != nil {
+			b.Fatal("unexpected error:", err)
+		}
+
+		b.ResetTimer()
+		for range b.N {
+			a.Ignore("src/github.com/hattya/aster")
+		}
+	})
+	if err != nil {
+		b.Error(err)
+	}
+}
+
 func TestWatchArgs(t *testing.T) {
 	err := test.Sandbox(func() {
 		src := `aster.watch(/.+\.go/, function() { });`
@@ -0,0 +1,136 @@
+//
+// aster :: check.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hattya/go.cli"
+	"github.com/robertkrimen/otto"
+)
+
+// Check evaluates the Asterfile name without running commands, writing files
+// and sending notifications. It returns the problems of the Asterfile, or an
+// error if it cannot be evaluated.
+func Check(ui *cli.CLI, name string, opts ...Option) ([]string, error) {
+	a, err := load(ui, name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := a.problems
+	// patterns which match no files
+	files, err := a.tree(filepath.Dir(name))
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range a.watches {
+		if w.builtin {
+			continue
+		}
+		found := false
+		for _, n := range files {
+			s, dir := strings.CutSuffix(n, string(os.PathSeparator))
+			if dir && !w.dirs {
+				continue
+			}
+			if v, _ := w.rx.Call("test", s); v.IsBoolean() {
+				if found, _ = v.ToBoolean(); found {
+					break
+				}
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("%v: aster.watch: %v matches no files", w.loc, w.rx.Value()))
+		}
+	}
+	return problems, nil
+}
+
+// load evaluates the Asterfile name without running commands, writing files
+// and sending notifications.
+func load(ui *cli.CLI, name string, opts []Option) (*Aster, error) {
+	a := &Aster{
+		ui:   ui,
+		file: name,
+	}
+	for _, o := range opts {
+		o(a)
+	}
+	a.dry = true
+	a.quiet = true
+	a.log = slog.New(discardHandler{})
+	if err := a.eval(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// tree returns the files and directories under the roots which are not
+// ignored. Directories end with a path separator, and paths which cannot be
+// read are skipped.
+func (a *Aster) tree(dir string) ([]string, error) {
+	var files []string
+	for _, r := range a.Roots() {
+		root := filepath.Join(dir, r)
+		var vi *vcsIgnore
+		if a.IgnoreVCS() {
+			vi = newVCSIgnore(root)
+		}
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			switch {
+			case path == root:
+				return err
+			case err != nil:
+				if d != nil && d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			rel, _ := filepath.Rel(root, path)
+			name, _ := filepath.Rel(dir, path)
+			if d.IsDir() {
+				if a.Ignore(rel) || (vi != nil && vi.Match(rel, true)) {
+					return filepath.SkipDir
+				}
+				files = append(files, name+string(os.PathSeparator))
+			} else if !a.Ignore(rel) && !a.TempFile(rel) && (vi == nil || !vi.Match(rel, false)) {
+				files = append(files, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// problem records a problem of the Asterfile.
+func (a *Aster) problem(call otto.FunctionCall, msg string) {
+	if loc := location(call); loc != "" {
+		msg = loc + ": " + msg
+	}
+	a.problems = append(a.problems, msg)
+}
+
+// location returns the location where call is called.
+func location(call otto.FunctionCall) (loc string) {
+	defer func() {
+		if recover() != nil {
+			loc = ""
+		}
+	}()
+	return call.CallerLocation()
+}
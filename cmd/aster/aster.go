@@ -1,7 +1,7 @@
 //
 // aster/cmd/aster :: aster.go
 //
-//   Copyright (c) 2014-2025 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -13,6 +13,7 @@ import (
 	"os/signal"
 	"runtime"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/hattya/aster"
@@ -42,6 +43,11 @@ func init() {
 	app.Usage = []string{
 		"[options]",
 		"init [<template>...]",
+		"check [<file>]",
+		"explain <path>...",
+		"list [options]",
+		"run [<path>...]",
+		"ctl <command> [<file>...]",
 	}
 	app.Epilog = strings.TrimSpace(cli.Dedent(`
 		<impl> is one of the following:
@@ -54,13 +60,30 @@ func init() {
 		<duration> is an integer and time unit. Valid time units are "ns", "us", "ms",
 		"s", "m", and "h"
 	`))
+	app.Flags.Bool("a", false, "run all watches on startup")
+	app.Flags.Bool("debug", false, "log events, ignore decisions and matches")
+	app.Flags.Bool("dry-run", false, "log what would be performed without performing it")
 	var g aster.GNTPValue
 	app.Flags.Var("g", &g, "notify to Growl (default: localhost:23053)")
 	app.Flags.MetaVar("g", "[=<host>[:<port>]]")
+	app.Flags.Bool("hash", false, "skip events of files whose contents have not been changed")
+	app.Flags.Bool("L", false, "follow symbolic links")
+	app.Flags.String("log", "", "write logs to <file> instead of stderr")
+	app.Flags.MetaVar("log", " <file>")
+	app.Flags.String("log-json", "", "write a JSON log of each cycle to <file>")
+	app.Flags.MetaVar("log-json", " <file>")
 	app.Flags.PrefixChoice("n", "", impls, "notifier implementation")
 	app.Flags.MetaVar("n", " <impl>")
 	app.Flags.Duration("s", 727*time.Millisecond, "squash events during <duration> (default: %v)")
 	app.Flags.MetaVar("s", " <duration>")
+	var state aster.StateValue
+	app.Flags.Var("state", &state, "process files changed while aster was not running")
+	app.Flags.MetaVar("state", "[=hash]")
+	app.Flags.Bool("stdin", false, "read changed files from stdin instead of watching")
+	app.Flags.Bool("v", false, "log batches and matches")
+	var roots aster.RootValue
+	app.Flags.Var("C, w", &roots, "watch <dir> in addition to the current directory")
+	app.Flags.MetaVar("w", " <dir>")
 	app.Action = cli.Option(watch)
 }
 
@@ -93,24 +116,83 @@ func watch(ctx *cli.Context) error {
 			}
 		}
 	}
-	a, err := aster.New(ctx.UI, n)
+	options := []aster.Option{
+		aster.DetachStdin(ctx.Bool("stdin")),
+		aster.DryRun(ctx.Bool("dry-run")),
+		aster.FollowSymlinks(ctx.Bool("L")),
+		aster.Roots(ctx.Value("w").([]string)...),
+	}
+	switch l, c, err := newLogger(ctx); {
+	case err != nil:
+		return err
+	case l != nil:
+		options = append(options, aster.Log(l))
+		if c != nil {
+			defer c.Close()
+		}
+	}
+	if name := ctx.String("log-json"); name != "" {
+		f, err := openLog(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		options = append(options, aster.JSONLog(f))
+	}
+	var t *terminal
+	if !ctx.Bool("stdin") {
+		t = newTerminal(ctx.UI)
+	}
+	if t != nil {
+		options = append(options, aster.Term(t))
+	}
+	a, err := aster.New(ctx.UI, n, options...)
 	if err != nil {
 		return err
 	}
 
 	sig := make(chan os.Signal, 1)
-	signal.Notify(sig, os.Interrupt)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-sig
 		ctx.Interrupt()
 	}()
 
-	w, err := aster.NewWatcher(ctx.Context(), a)
+	w, err := aster.NewWatcher(ctx.Context(), a, aster.External(ctx.Bool("stdin")))
 	if err != nil {
 		return err
 	}
 	defer w.Close()
 
 	w.Squash = ctx.Duration("s")
+	if ctx.Bool("hash") {
+		w.Digests = 8192
+	}
+	if ctx.Bool("a") {
+		w.RunAll()
+	}
+	if ctx.Bool("stdin") {
+		w.Read(os.Stdin)
+	}
+	if s := ctx.String("state"); s != "" {
+		if err := w.CatchUp(aster.StateFile); err != nil {
+			ctx.UI.Errorf("%v: %v\n", ctx.UI.Name, err)
+		}
+		defer func() {
+			if err := w.SaveState(aster.StateFile, s == "hash"); err != nil {
+				ctx.UI.Errorf("%v: %v\n", ctx.UI.Name, err)
+			}
+		}()
+	}
+	if err := w.Listen(aster.ControlSocket); err != nil {
+		ctx.UI.Errorf("%v: %v\n", ctx.UI.Name, err)
+	}
+	defer handleSignals(w)()
+	if t != nil {
+		if err := t.Start(w); err != nil {
+			return err
+		}
+		defer t.Stop()
+	}
 	return w.Watch()
 }
@@ -38,6 +38,32 @@ func TestInterrupt(t *testing.T) {
 	}
 }
 
+func TestRoot(t *testing.T) {
+	app := clone()
+	time.AfterFunc(101*time.Millisecond, app.Interrupt)
+	err := test.Sandbox(func() {
+		if err := sh.Touch("Asterfile"); err != nil {
+			t.Fatal(err)
+		}
+		// not found
+		if err := app.Run([]string{"-w", "src"}); err == nil {
+			t.Fatal("expected error")
+		}
+
+		if err := sh.Mkdir("src"); err != nil {
+			t.Fatal(err)
+		}
+		switch err := app.Run([]string{"-w", "src"}).(type) {
+		case cli.Interrupt:
+		default:
+			t.Errorf("expected cli.Interrupt, got %#v", err)
+		}
+	})
+	if err != nil {
+		t.Error()
+	}
+}
+
 func TestNotifier(t *testing.T) {
 	tests := [][]string{
 		{"-g"},
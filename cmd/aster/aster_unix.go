@@ -1,7 +1,7 @@
 //
 // aster/cmd/aster :: aster_unix.go
 //
-//   Copyright (c) 2017-2025 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2017-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -11,6 +11,11 @@
 package main
 
 import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/hattya/aster"
 	"github.com/hattya/go.notify"
 	"github.com/hattya/go.notify/freedesktop"
 )
@@ -40,3 +45,30 @@ func newNotifier(name, impl string) (n notify.Notifier) {
 	}
 	return
 }
+
+// handleSignals handles SIGHUP to reload the Asterfile, SIGUSR1 to run all
+// watches, and SIGUSR2 to toggle the pause.
+func handleSignals(w *aster.Watcher) (stop func()) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP, syscall.SIGUSR1, syscall.SIGUSR2)
+	go func() {
+		for s := range sig {
+			switch s {
+			case syscall.SIGHUP:
+				w.Reload()
+			case syscall.SIGUSR1:
+				w.RunAll()
+			case syscall.SIGUSR2:
+				if w.Paused() {
+					w.Resume()
+				} else {
+					w.Pause()
+				}
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sig)
+		close(sig)
+	}
+}
@@ -1,7 +1,7 @@
 //
 // aster/cmd/aster :: aster_windows.go
 //
-//   Copyright (c) 2017-2025 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2017-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -9,6 +9,7 @@
 package main
 
 import (
+	"github.com/hattya/aster"
 	"github.com/hattya/go.notify"
 	"github.com/hattya/go.notify/freedesktop"
 	"github.com/hattya/go.notify/windows"
@@ -56,3 +57,7 @@ func newNotifier(name, impl string) (n notify.Notifier) {
 	}
 	return
 }
+
+func handleSignals(*aster.Watcher) (stop func()) {
+	return func() {}
+}
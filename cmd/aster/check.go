@@ -0,0 +1,63 @@
+//
+// aster/cmd/aster :: check.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hattya/aster"
+	"github.com/hattya/go.cli"
+)
+
+func init() {
+	app.Add(&cli.Command{
+		Name:  []string{"check"},
+		Usage: "[<file>]",
+		Desc: strings.TrimSpace(cli.Dedent(`
+			validate an Asterfile without watching
+
+			  Evaluate the Asterfile in the current directory, or the specified file,
+			  without running commands, writing files and sending notifications.
+
+			  It reports errors, invalid arguments of aster.watch, and patterns which
+			  match no files.
+		`)),
+		Flags:  cli.NewFlagSet(),
+		Action: check,
+	})
+}
+
+func check(ctx *cli.Context) error {
+	name := "Asterfile"
+	switch len(ctx.Args) {
+	case 0:
+	case 1:
+		name = ctx.Args[0]
+	default:
+		return cli.ErrArgs
+	}
+
+	problems, err := aster.Check(ctx.UI, name)
+	if err != nil {
+		return err
+	}
+	for _, s := range problems {
+		ctx.UI.Errorln(s)
+	}
+	switch len(problems) {
+	case 0:
+		return nil
+	case 1:
+		return errors.New("1 problem found")
+	default:
+		return fmt.Errorf("%v problems found", len(problems))
+	}
+}
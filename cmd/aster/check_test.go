@@ -0,0 +1,105 @@
+//
+// aster/cmd/aster :: check_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hattya/aster/internal/sh"
+	"github.com/hattya/aster/internal/test"
+	"github.com/hattya/go.cli"
+)
+
+func TestCheck(t *testing.T) {
+	stderr := app.Stderr
+	defer func() { app.Stderr = stderr }()
+
+	var b strings.Builder
+	app.Stderr = &b
+	err := test.Sandbox(func() error {
+		if err := sh.Touch("a.go"); err != nil {
+			return err
+		}
+		// no problems
+		src := cli.Dedent(`
+			var os = require('os');
+			os.system(['rm', 'a.go']);
+			aster.watch(/.+\.go$/, function() {});
+		`)
+		if err := test.Gen(src); err != nil {
+			return err
+		}
+		if err := app.Run([]string{"check"}); err != nil {
+			t.Error(err)
+		}
+		if _, err := os.Stat("a.go"); err != nil {
+			t.Error(err)
+		}
+		if g, e := b.String(), ""; g != e {
+			t.Errorf("expected %q, got %q", e, g)
+		}
+		// VCS ignore files
+		if err := os.WriteFile(".gitignore", []byte("*.log\n"), 0o666); err != nil {
+			return err
+		}
+		if err := sh.Touch("a.log"); err != nil {
+			return err
+		}
+		src = cli.Dedent(`
+			aster.ignoreVCS = true;
+			aster.watch(/.+\.log$/, function() {});
+		`)
+		if err := test.Gen(src); err != nil {
+			return err
+		}
+		if err := app.Run([]string{"check"}); err == nil {
+			t.Error("expected error")
+		}
+		if g, e := b.String(), "Asterfile:2:1: aster.watch: /.+\\.log$/ matches no files\n"; !strings.Contains(g, e) {
+			t.Errorf("expected %q to contain %q", g, e)
+		}
+		// invalid arguments & no files
+		src = cli.Dedent(`
+			aster.watch('.go', function() {});
+			aster.watch(/.+\.txt$/, function() {});
+		`)
+		if err := os.WriteFile("a.aster", []byte(src), 0o666); err != nil {
+			return err
+		}
+		b.Reset()
+		if err := app.Run([]string{"check", "a.aster"}); err == nil {
+			t.Error("expected error")
+		}
+		for _, e := range []string{
+			"a.aster:1:1: aster.watch: pattern is not a RegExp\n",
+			"a.aster:2:1: aster.watch: /.+\\.txt$/ matches no files\n",
+		} {
+			if g := b.String(); !strings.Contains(g, e) {
+				t.Errorf("expected %q to contain %q", g, e)
+			}
+		}
+		// syntax error
+		if err := test.Gen(`++;`); err != nil {
+			return err
+		}
+		if err := app.Run([]string{"check"}); err == nil {
+			t.Error("expected error")
+		}
+		// invalid arguments
+		if err := app.Run([]string{"check", "a", "b"}); err != cli.ErrArgs {
+			t.Errorf("expected cli.ErrArgs, got %#v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
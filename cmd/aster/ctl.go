@@ -0,0 +1,65 @@
+//
+// aster/cmd/aster :: ctl.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/hattya/aster"
+	"github.com/hattya/go.cli"
+)
+
+func init() {
+	app.Add(&cli.Command{
+		Name:  []string{"ctl"},
+		Usage: "<command> [<file>...]",
+		Desc: strings.TrimSpace(cli.Dedent(`
+			control a running aster
+
+			  Send a command to the aster which is running in the current directory.
+
+			  Commands:
+
+			  status:            show the status
+			  trigger <file>...: process files as if they have been changed
+			  reload:            reload the Asterfile
+			  run-all:           run all watches
+			  pause:             pause invoking callbacks
+			  resume:            resume invoking callbacks
+		`)),
+		Flags:  cli.NewFlagSet(),
+		Action: ctl,
+	})
+}
+
+func ctl(ctx *cli.Context) error {
+	if len(ctx.Args) == 0 {
+		return cli.ErrArgs
+	}
+
+	resp, err := aster.Control(aster.ControlSocket, &aster.Request{
+		Command: ctx.Args[0],
+		Files:   ctx.Args[1:],
+	})
+	if err != nil {
+		return err
+	}
+	if st := resp.Status; st != nil {
+		state := "watching"
+		if st.Paused {
+			state = "paused"
+		}
+		ctx.UI.Printf("pid:     %v\n", st.PID)
+		ctx.UI.Printf("state:   %v\n", state)
+		ctx.UI.Printf("roots:   %v\n", strings.Join(st.Roots, string(os.PathListSeparator)))
+		ctx.UI.Printf("watches: %v\n", st.Watches)
+	}
+	return nil
+}
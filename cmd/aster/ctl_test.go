@@ -0,0 +1,76 @@
+//
+// aster/cmd/aster :: ctl_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hattya/aster"
+	"github.com/hattya/aster/internal/sh"
+	"github.com/hattya/aster/internal/test"
+	"github.com/hattya/go.cli"
+)
+
+func TestCtl(t *testing.T) {
+	stdout, stderr := app.Stdout, app.Stderr
+	defer func() {
+		app.Stdout, app.Stderr = stdout, stderr
+	}()
+
+	var b strings.Builder
+	app.Stdout = &b
+	app.Stderr = io.Discard
+	err := test.Sandbox(func() error {
+		if err := sh.Touch("Asterfile"); err != nil {
+			return err
+		}
+		// not running
+		if err := app.Run([]string{"ctl", "status"}); err == nil {
+			t.Error("expected error")
+		}
+
+		a, err := test.New()
+		if err != nil {
+			return err
+		}
+		w, err := aster.NewWatcher(context.Background(), a)
+		if err != nil {
+			return err
+		}
+		defer w.Close()
+
+		go w.Watch()
+		if err := w.Listen(aster.ControlSocket); err != nil {
+			return err
+		}
+		if err := app.Run([]string{"ctl", "status"}); err != nil {
+			t.Error(err)
+		}
+		if g, e := b.String(), "state:   watching\n"; !strings.Contains(g, e) {
+			t.Errorf("expected %q to contain %q", g, e)
+		}
+		// invalid arguments
+		if err := app.Run([]string{"ctl"}); err != cli.ErrArgs {
+			t.Errorf("expected cli.ErrArgs, got %#v", err)
+		}
+		if err := app.Run([]string{"ctl", "trigger"}); err == nil {
+			t.Error("expected error")
+		}
+		if err := app.Run([]string{"ctl", "unknown"}); err == nil {
+			t.Error("expected error")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
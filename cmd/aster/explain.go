@@ -0,0 +1,79 @@
+//
+// aster/cmd/aster :: explain.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package main
+
+import (
+	"strings"
+
+	"github.com/hattya/aster"
+	"github.com/hattya/go.cli"
+)
+
+func init() {
+	app.Add(&cli.Command{
+		Name:  []string{"explain"},
+		Usage: "<path>...",
+		Desc: strings.TrimSpace(cli.Dedent(`
+			show why a path is ignored or which watch handles it
+
+			  Evaluate the Asterfile in the current directory like the check command,
+			  and show for each path:
+
+			  root:   the watched directory which contains it
+			  ignore: the pattern of aster.ignore, aster.tempFiles or VCS ignore files
+			          which excludes it
+			  watch:  the pattern of aster.watch which receives it
+			  also:   the patterns of aster.watch which match it, but are shadowed by
+			          the preceding one
+		`)),
+		Flags:  cli.NewFlagSet(),
+		Action: explain,
+	})
+}
+
+func explain(ctx *cli.Context) error {
+	if len(ctx.Args) == 0 {
+		return cli.ErrArgs
+	}
+
+	list, err := aster.Explain(ctx.UI, ctx.Args)
+	if err != nil {
+		return err
+	}
+	for i, e := range list {
+		if i > 0 {
+			ctx.UI.Println()
+		}
+		ctx.UI.Println(e.Path)
+		if e.Root == "" {
+			ctx.UI.Println("  root:   none (not under a watched directory)")
+			continue
+		}
+		ctx.UI.Printf("  root:   %v\n", e.Root)
+		switch {
+		case e.Ignore != "":
+			ctx.UI.Printf("  ignore: %v\n", e.Ignore)
+			ctx.UI.Println("  watch:  none (ignored)")
+			continue
+		case len(e.Watches) == 0:
+			ctx.UI.Println("  ignore: none")
+			ctx.UI.Println("  watch:  none")
+			continue
+		}
+		ctx.UI.Println("  ignore: none")
+		for j, s := range e.Watches {
+			if j == 0 {
+				ctx.UI.Printf("  watch:  %v\n", s)
+			} else {
+				ctx.UI.Printf("  also:   %v\n", s)
+			}
+		}
+	}
+	return nil
+}
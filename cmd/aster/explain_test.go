@@ -0,0 +1,97 @@
+//
+// aster/cmd/aster :: explain_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package main
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hattya/aster/internal/test"
+	"github.com/hattya/go.cli"
+)
+
+func TestExplain(t *testing.T) {
+	stdout, stderr := app.Stdout, app.Stderr
+	defer func() {
+		app.Stdout, app.Stderr = stdout, stderr
+	}()
+
+	var b strings.Builder
+	app.Stdout = &b
+	app.Stderr = io.Discard
+	err := test.Sandbox(func() error {
+		src := cli.Dedent(`
+			aster.ignore.push(/^build$/);
+			aster.watch(/.+\.go$/, function() {});
+			aster.watch(/.*/, function() {});
+		`)
+		if err := test.Gen(src); err != nil {
+			return err
+		}
+		args := []string{
+			"explain",
+			"a.go",
+			"a.txt",
+			"Asterfile",
+			filepath.Join("build", "a.go"),
+			"a.go~",
+			filepath.Join("..", "a.go"),
+		}
+		if err := app.Run(args); err != nil {
+			t.Fatal(err)
+		}
+		r := strings.NewReplacer(
+			"build/a.go", filepath.Join("build", "a.go"),
+			"../a.go", filepath.Join("..", "a.go"),
+		)
+		if g, e := b.String(), r.Replace(cli.Dedent(`
+			a.go
+			  root:   .
+			  ignore: none
+			  watch:  /.+\.go$/ (Asterfile:2:1)
+			  also:   /.*/ (Asterfile:3:1)
+
+			a.txt
+			  root:   .
+			  ignore: none
+			  watch:  /.*/ (Asterfile:3:1)
+
+			Asterfile
+			  root:   .
+			  ignore: none
+			  watch:  /^Asterfile$/ (builtin)
+			  also:   /.*/ (Asterfile:3:1)
+
+			build/a.go
+			  root:   .
+			  ignore: /^build$/
+			  watch:  none (ignored)
+
+			a.go~
+			  root:   .
+			  ignore: /~$/
+			  watch:  none (ignored)
+
+			../a.go
+			  root:   none (not under a watched directory)
+		`)); g != e {
+			t.Errorf("expected %q, got %q", e, g)
+		}
+		// invalid arguments
+		if err := app.Run([]string{"explain"}); err != cli.ErrArgs {
+			t.Errorf("expected cli.ErrArgs, got %#v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
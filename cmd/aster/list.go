@@ -0,0 +1,99 @@
+//
+// aster/cmd/aster :: list.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package main
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/hattya/aster"
+	"github.com/hattya/go.cli"
+)
+
+func init() {
+	flags := cli.NewFlagSet()
+	flags.Bool("files", false, "show files which each watch receives")
+	flags.Bool("json", false, "output in JSON")
+
+	app.Add(&cli.Command{
+		Name:  []string{"list"},
+		Usage: "[options]",
+		Desc: strings.TrimSpace(cli.Dedent(`
+			list watches of the Asterfile
+
+			  Evaluate the Asterfile in the current directory like the check command,
+			  and show each watch with its pattern, where it is defined and its options.
+
+			  With the -files flag, it also shows the existing files which each watch
+			  receives.
+		`)),
+		Flags:  flags,
+		Action: list,
+	})
+}
+
+func list(ctx *cli.Context) error {
+	if len(ctx.Args) != 0 {
+		return cli.ErrArgs
+	}
+
+	watches, err := aster.List(ctx.UI, ctx.Bool("files"))
+	if err != nil {
+		return err
+	}
+	if ctx.Bool("json") {
+		if watches == nil {
+			watches = []*aster.WatchInfo{}
+		}
+		b, err := json.MarshalIndent(watches, "", "  ")
+		if err != nil {
+			return err
+		}
+		ctx.UI.Println(string(b))
+		return nil
+	}
+	for _, wi := range watches {
+		var opts []string
+		for _, o := range []struct {
+			k string
+			b bool
+			s string
+		}{
+			{k: "dirs", b: wi.Options.Dirs},
+			{k: "renames", b: wi.Options.Renames},
+			{k: "debounce", s: wi.Options.Debounce},
+			{k: "throttle", s: wi.Options.Throttle},
+			{k: "leading", b: wi.Options.Leading},
+			{k: "runAll", b: wi.Options.RunAll},
+		} {
+			switch {
+			case o.b:
+				opts = append(opts, o.k)
+			case o.s != "":
+				opts = append(opts, o.k+"="+o.s)
+			}
+		}
+		if len(wi.Options.Outputs) > 0 {
+			opts = append(opts, "outputs=["+strings.Join(wi.Options.Outputs, ", ")+"]")
+		}
+		loc := wi.Location
+		if wi.Builtin {
+			loc = "builtin"
+		}
+		ctx.UI.Printf("%v: %v", loc, wi.Pattern)
+		if len(opts) > 0 {
+			ctx.UI.Printf(" (%v)", strings.Join(opts, ", "))
+		}
+		ctx.UI.Println()
+		for _, n := range wi.Files {
+			ctx.UI.Printf("  %v\n", n)
+		}
+	}
+	return nil
+}
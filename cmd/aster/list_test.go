@@ -0,0 +1,96 @@
+//
+// aster/cmd/aster :: list_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hattya/aster"
+	"github.com/hattya/aster/internal/sh"
+	"github.com/hattya/aster/internal/test"
+	"github.com/hattya/go.cli"
+)
+
+func TestList(t *testing.T) {
+	stdout, stderr := app.Stdout, app.Stderr
+	defer func() {
+		app.Stdout, app.Stderr = stdout, stderr
+	}()
+
+	var b strings.Builder
+	app.Stdout = &b
+	app.Stderr = io.Discard
+	err := test.Sandbox(func() error {
+		for _, n := range []string{"a.go", "a.txt"} {
+			if err := sh.Touch(n); err != nil {
+				return err
+			}
+		}
+		if err := sh.Mkdir("src"); err != nil {
+			return err
+		}
+		if err := sh.Touch("src", "b.go"); err != nil {
+			return err
+		}
+		src := cli.Dedent(`
+			aster.watch(/.+\.go$/, function() {}, { debounce: 500, leading: true });
+			aster.watch(/.*/, function() {}, { dirs: true, outputs: ['out.txt', /\.html$/] });
+		`)
+		if err := test.Gen(src); err != nil {
+			return err
+		}
+		if err := app.Run([]string{"list"}); err != nil {
+			t.Fatal(err)
+		}
+		if g, e := b.String(), cli.Dedent(`
+			builtin: /^Asterfile$/
+			Asterfile:1:1: /.+\.go$/ (debounce=500ms, leading)
+			Asterfile:2:1: /.*/ (dirs, outputs=[out.txt, /\.html$/])
+		`); g != e {
+			t.Errorf("expected %q, got %q", e, g)
+		}
+
+		b.Reset()
+		if err := app.Run([]string{"list", "-files", "-json"}); err != nil {
+			t.Fatal(err)
+		}
+		var list []*aster.WatchInfo
+		if err := json.Unmarshal([]byte(b.String()), &list); err != nil {
+			t.Fatal(err)
+		}
+		if g, e := len(list), 3; g != e {
+			t.Fatalf("expected %v, got %v", e, g)
+		}
+		if !list[0].Builtin {
+			t.Error("expected builtin")
+		}
+		if g, e := list[0].Files, []string{"Asterfile"}; !reflect.DeepEqual(g, e) {
+			t.Errorf("expected %v, got %v", e, g)
+		}
+		if g, e := list[1].Files, []string{"a.go", filepath.Join("src", "b.go")}; !reflect.DeepEqual(g, e) {
+			t.Errorf("expected %v, got %v", e, g)
+		}
+		if g, e := list[2].Files, []string{"a.txt", "src" + string(filepath.Separator)}; !reflect.DeepEqual(g, e) {
+			t.Errorf("expected %v, got %v", e, g)
+		}
+		// invalid arguments
+		if err := app.Run([]string{"list", "a"}); err != cli.ErrArgs {
+			t.Errorf("expected cli.ErrArgs, got %#v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
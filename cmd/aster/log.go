@@ -0,0 +1,47 @@
+//
+// aster/cmd/aster :: log.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/hattya/go.cli"
+)
+
+// newLogger returns the logger specified by the -v, -debug and -log flags.
+// It returns nil when logging is disabled.
+func newLogger(ctx *cli.Context) (*slog.Logger, io.Closer, error) {
+	var level slog.Level
+	switch {
+	case ctx.Bool("debug"):
+		level = slog.LevelDebug
+	case ctx.Bool("v"):
+		level = slog.LevelInfo
+	default:
+		return nil, nil, nil
+	}
+
+	w := ctx.UI.Stderr
+	var c io.Closer
+	if name := ctx.String("log"); name != "" {
+		f, err := openLog(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		w = f
+		c = f
+	}
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})), c, nil
+}
+
+func openLog(name string) (*os.File, error) {
+	return os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o666)
+}
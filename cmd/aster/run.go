@@ -0,0 +1,60 @@
+//
+// aster/cmd/aster :: run.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/hattya/aster"
+	"github.com/hattya/go.cli"
+)
+
+func init() {
+	app.Add(&cli.Command{
+		Name:  []string{"run"},
+		Usage: "[<path>...]",
+		Desc: strings.TrimSpace(cli.Dedent(`
+			process files once without watching
+
+			  Evaluate the Asterfile in the current directory, process the specified
+			  files, or all of the files in the watched directories, as if they have
+			  been changed, and exit.
+
+			  It exits with a non-zero status when a callback threw, or reported a
+			  failure by aster.notify (e.g. language.system).
+		`)),
+		Flags:  cli.NewFlagSet(),
+		Action: run,
+	})
+}
+
+func run(ctx *cli.Context) error {
+	a, err := aster.New(ctx.UI, nil)
+	if err != nil {
+		return err
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		if _, ok := <-sig; ok {
+			ctx.Interrupt()
+			a.Terminate()
+		}
+	}()
+	defer func() {
+		signal.Stop(sig)
+		close(sig)
+	}()
+
+	return a.Run(ctx.Context(), ctx.Args)
+}
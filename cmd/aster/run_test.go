@@ -0,0 +1,79 @@
+//
+// aster/cmd/aster :: run_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hattya/aster/internal/sh"
+	"github.com/hattya/aster/internal/test"
+	"github.com/hattya/go.cli"
+)
+
+func TestRun(t *testing.T) {
+	stderr := app.Stderr
+	defer func() { app.Stderr = stderr }()
+
+	app.Stderr = io.Discard
+	err := test.Sandbox(func() error {
+		for _, n := range []string{"a.go", "b.go", "a.txt"} {
+			if err := sh.Touch(n); err != nil {
+				return err
+			}
+		}
+		src := cli.Dedent(`
+			var os = require('os');
+			aster.watch(/.+\.go$/, function(files) {
+			  var f = new os.open('go.log', 'a');
+			  f.write(files.sort().join(' ') + '\n');
+			  f.close();
+			});
+			aster.watch(/^fail$/, function() {
+			  aster.notify('failure', 'title', 'text');
+			});
+			aster.watch(/^throw$/, function() {
+			  throw new Error('error');
+			});
+		`)
+		if err := test.Gen(src); err != nil {
+			return err
+		}
+		// all files
+		if err := app.Run([]string{"run"}); err != nil {
+			t.Error(err)
+		}
+		// specified files
+		if err := app.Run([]string{"run", "b.go"}); err != nil {
+			t.Error(err)
+		}
+		data, err := os.ReadFile("go.log")
+		if err != nil {
+			return err
+		}
+		if g, e := string(data), "a.go b.go\nb.go\n"; g != e {
+			t.Errorf("expected %q, got %q", e, g)
+		}
+		// failure
+		for _, n := range []string{"fail", "throw"} {
+			switch err := app.Run([]string{"run", n}); {
+			case err == nil:
+				t.Errorf("%v: expected error", n)
+			case !strings.Contains(err.Error(), "1 failure"):
+				t.Errorf("%v: unexpected error: %v", n, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
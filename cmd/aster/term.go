@@ -0,0 +1,175 @@
+//
+// aster/cmd/aster :: term.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package main
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hattya/aster"
+	"github.com/hattya/go.cli"
+	"golang.org/x/term"
+)
+
+// terminal handles commands from the terminal while watching.
+type terminal struct {
+	ui *cli.CLI
+	fd int
+
+	mu     sync.Mutex
+	state  *term.State // non-nil in raw mode
+	active bool
+	n      int // number of suspensions
+	quit   chan struct{}
+	done   chan struct{}
+}
+
+func newTerminal(ui *cli.CLI) *terminal {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil
+	}
+	return &terminal{
+		ui:   ui,
+		fd:   fd,
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+}
+
+func (t *terminal) Start(w *aster.Watcher) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.n == 0 {
+		if err := t.raw(); err != nil {
+			return err
+		}
+	}
+	t.active = true
+	go t.loop(w)
+	return nil
+}
+
+func (t *terminal) Stop() {
+	t.mu.Lock()
+	active := t.active
+	t.active = false
+	t.restore()
+	t.mu.Unlock()
+
+	if active {
+		close(t.quit)
+		<-t.done
+	}
+}
+
+// Suspend restores the terminal for child processes.
+func (t *terminal) Suspend() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.n++
+	t.restore()
+}
+
+func (t *terminal) Resume() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.n--; t.n == 0 && t.active {
+		t.raw()
+	}
+}
+
+func (t *terminal) raw() (err error) {
+	if t.state == nil {
+		t.state, err = makeRaw(t.fd)
+	}
+	return
+}
+
+func (t *terminal) restore() {
+	if t.state != nil {
+		term.Restore(t.fd, t.state)
+		t.state = nil
+	}
+}
+
+func (t *terminal) loop(w *aster.Watcher) {
+	defer close(t.done)
+
+	const interval = 100 * time.Millisecond
+	for {
+		select {
+		case <-t.quit:
+			return
+		default:
+		}
+
+		t.mu.Lock()
+		raw := t.state != nil
+		t.mu.Unlock()
+		if !raw {
+			// stdin is used by a child process
+			time.Sleep(interval)
+			continue
+		}
+		switch ok, err := poll(t.fd, interval); {
+		case err != nil:
+			return
+		case !ok:
+			continue
+		}
+
+		var s string
+		var err error
+		t.mu.Lock()
+		if t.state != nil {
+			s, err = read(t.fd)
+		}
+		t.mu.Unlock()
+		if err != nil {
+			return
+		}
+		for _, c := range s {
+			if !t.do(w, c) {
+				return
+			}
+		}
+	}
+}
+
+func (t *terminal) do(w *aster.Watcher, c rune) bool {
+	switch c {
+	case '\r', '\n':
+		w.RunAll()
+	case 'c':
+		clearScreen()
+	case 'p':
+		if w.Paused() {
+			w.Resume()
+			t.ui.Errorf("%v: resumed\n", t.ui.Name)
+		} else {
+			w.Pause()
+			t.ui.Errorf("%v: paused\n", t.ui.Name)
+		}
+	case 'q':
+		w.Close()
+		return false
+	case 'r':
+		w.Reload()
+	case 0x03:
+		// Ctrl-C
+		t.ui.Interrupt()
+		return false
+	}
+	return true
+}
@@ -0,0 +1,170 @@
+//
+// aster/cmd/aster :: term_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hattya/aster"
+	"github.com/hattya/aster/internal/sh"
+	"github.com/hattya/aster/internal/test"
+	"github.com/hattya/go.cli"
+)
+
+func TestTerminal(t *testing.T) {
+	stdout := os.Stdout
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	err := test.Sandbox(func() error {
+		src := cli.Dedent(`
+			aster.watch(/.+\.go$/, function() {});
+		`)
+		if err := test.Gen(src); err != nil {
+			return err
+		}
+		if err := sh.Touch("a.go"); err != nil {
+			return err
+		}
+		var b, jl bytes.Buffer
+		ui := cli.NewCLI()
+		ui.Name = "aster.test"
+		ui.Stderr = &b
+		a, err := aster.New(ui, nil, aster.JSONLog(&jl))
+		if err != nil {
+			return err
+		}
+		w, err := aster.NewWatcher(context.Background(), a)
+		if err != nil {
+			return err
+		}
+		defer w.Close()
+
+		w.Squash = 101 * time.Millisecond
+		done := make(chan error, 1)
+		go func() {
+			done <- w.Watch()
+		}()
+		d := 2 * w.Squash
+		tm := &terminal{ui: ui}
+		feed := func(s string) bool {
+			for _, c := range s {
+				if !tm.do(w, c) {
+					return false
+				}
+			}
+			return true
+		}
+		// run all
+		if !feed("\r") {
+			t.Error("expected true")
+		}
+		time.Sleep(d)
+		if g, e := jl.String(), `"type":"run-all"`; !strings.Contains(g, e) {
+			t.Errorf("expected %q to contain %q", g, e)
+		}
+		// pause & resume
+		if !feed("p") {
+			t.Error("expected true")
+		}
+		if !w.Paused() {
+			t.Error("expected to be paused")
+		}
+		if !feed("p") {
+			t.Error("expected true")
+		}
+		if w.Paused() {
+			t.Error("expected to be resumed")
+		}
+		if g, e := b.String(), "aster.test: paused\naster.test: resumed\n"; g != e {
+			t.Errorf("expected %q, got %q", e, g)
+		}
+		// clear
+		f, err := os.Create("stdout")
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		os.Stdout = f
+		if !feed("c") {
+			t.Error("expected true")
+		}
+		os.Stdout = stdout
+		if data, err := os.ReadFile("stdout"); err != nil {
+			t.Error(err)
+		} else if g, e := string(data), "\x1b[H\x1b[2J"; g != e {
+			t.Errorf("expected %q, got %q", e, g)
+		}
+		// reload
+		if _, err := a.Eval(`var reloaded = false;`); err != nil {
+			return err
+		}
+		if !feed("r") {
+			t.Error("expected true")
+		}
+		time.Sleep(d)
+		if v, _ := a.Eval(`typeof reloaded;`); v.String() != "undefined" {
+			t.Error("expected to be reloaded")
+		}
+		// quit
+		if feed("qr") {
+			t.Error("expected false")
+		}
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Error("timeout")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTerminalInterrupt(t *testing.T) {
+	err := test.Sandbox(func() error {
+		if err := sh.Touch("Asterfile"); err != nil {
+			return err
+		}
+		a, err := test.New()
+		if err != nil {
+			return err
+		}
+		w, err := aster.NewWatcher(context.Background(), a)
+		if err != nil {
+			return err
+		}
+		defer w.Close()
+
+		go w.Watch()
+		ui := cli.NewCLI()
+		ui.Action = func(ctx *cli.Context) error {
+			tm := &terminal{ui: ctx.UI}
+			if tm.do(w, 0x03) {
+				t.Error("expected false")
+			}
+			if ctx.Context().Err() == nil {
+				t.Error("expected to be interrupted")
+			}
+			return nil
+		}
+		ui.Run(nil)
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
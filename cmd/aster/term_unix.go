@@ -0,0 +1,69 @@
+//
+// aster/cmd/aster :: term_unix.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+)
+
+func makeRaw(fd int) (*term.State, error) {
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	// keep output processing and signals
+	termios, err := unix.IoctlGetTermios(fd, ioctlReadTermios)
+	if err != nil {
+		term.Restore(fd, state)
+		return nil, err
+	}
+	termios.Oflag |= unix.OPOST
+	termios.Lflag |= unix.ISIG
+	if err := unix.IoctlSetTermios(fd, ioctlWriteTermios, termios); err != nil {
+		term.Restore(fd, state)
+		return nil, err
+	}
+	return state, nil
+}
+
+func poll(fd int, timeout time.Duration) (bool, error) {
+	fds := []unix.PollFd{{
+		Fd:     int32(fd),
+		Events: unix.POLLIN,
+	}}
+	for {
+		n, err := unix.Poll(fds, int(timeout.Milliseconds()))
+		switch {
+		case err == unix.EINTR:
+			continue
+		case err != nil:
+			return false, err
+		}
+		return n > 0, nil
+	}
+}
+
+func read(fd int) (string, error) {
+	var b [64]byte
+	n, err := unix.Read(fd, b[:])
+	if err != nil {
+		return "", err
+	}
+	return string(b[:n]), nil
+}
+
+func clearScreen() {
+	os.Stdout.WriteString("\x1b[H\x1b[2J")
+}
@@ -0,0 +1,104 @@
+//
+// aster/cmd/aster :: term_windows.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"time"
+	"unicode/utf16"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/term"
+)
+
+func makeRaw(fd int) (*term.State, error) {
+	return term.MakeRaw(fd)
+}
+
+func poll(fd int, timeout time.Duration) (bool, error) {
+	ev, err := windows.WaitForSingleObject(windows.Handle(fd), uint32(timeout.Milliseconds()))
+	switch ev {
+	case windows.WAIT_OBJECT_0:
+		return true, nil
+	case uint32(windows.WAIT_TIMEOUT):
+		return false, nil
+	}
+	return false, err
+}
+
+type inputRecord struct {
+	eventType uint16
+	_         uint16
+	// KEY_EVENT_RECORD
+	keyDown         int32
+	repeatCount     uint16
+	virtualKeyCode  uint16
+	virtualScanCode uint16
+	unicodeChar     uint16
+	controlKeyState uint32
+}
+
+func read(fd int) (string, error) {
+	var n uint32
+	if err := getNumberOfConsoleInputEvents(windows.Handle(fd), &n); err != nil || n == 0 {
+		return "", err
+	}
+	recs := make([]inputRecord, n)
+	if err := readConsoleInput(windows.Handle(fd), &recs[0], n, &n); err != nil {
+		return "", err
+	}
+
+	var s []uint16
+	for _, r := range recs[:n] {
+		if r.eventType == windows.KEY_EVENT && r.keyDown != 0 && r.unicodeChar != 0 {
+			for range max(r.repeatCount, 1) {
+				s = append(s, r.unicodeChar)
+			}
+		}
+	}
+	return string(utf16.Decode(s)), nil
+}
+
+func clearScreen() {
+	h := windows.Handle(os.Stdout.Fd())
+	var mode uint32
+	if windows.GetConsoleMode(h, &mode) == nil && windows.SetConsoleMode(h, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil {
+		defer windows.SetConsoleMode(h, mode)
+	}
+	os.Stdout.WriteString("\x1b[H\x1b[2J")
+}
+
+var (
+	kernel32 = windows.NewLazySystemDLL("kernel32.dll")
+
+	pGetNumberOfConsoleInputEvents = kernel32.NewProc("GetNumberOfConsoleInputEvents")
+	pReadConsoleInput              = kernel32.NewProc("ReadConsoleInputW")
+)
+
+func getNumberOfConsoleInputEvents(console windows.Handle, n *uint32) error {
+	r1, _, e1 := pGetNumberOfConsoleInputEvents.Call(uintptr(console), uintptr(unsafe.Pointer(n)))
+	return winError(r1, e1)
+}
+
+func readConsoleInput(console windows.Handle, buf *inputRecord, size uint32, n *uint32) error {
+	r1, _, e1 := pReadConsoleInput.Call(uintptr(console), uintptr(unsafe.Pointer(buf)), uintptr(size), uintptr(unsafe.Pointer(n)))
+	return winError(r1, e1)
+}
+
+func winError(r1 uintptr, e1 error) error {
+	if r1 != 0 {
+		return nil
+	}
+	if errno, ok := e1.(syscall.Errno); ok && errno != 0 {
+		return e1
+	}
+	return syscall.EINVAL
+}
@@ -0,0 +1,155 @@
+//
+// aster :: control.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ControlSocket is the path of the control socket.
+var ControlSocket = filepath.Join(".aster", "sock")
+
+// Request is a request to the control socket. A request and its response
+// are encoded as a line of JSON.
+type Request struct {
+	Command string   `json:"command"`
+	Files   []string `json:"files,omitempty"`
+}
+
+// Response is a response from the control socket.
+type Response struct {
+	Error  string  `json:"error,omitempty"`
+	Status *Status `json:"status,omitempty"`
+}
+
+type Status struct {
+	PID     int      `json:"pid"`
+	Paused  bool     `json:"paused"`
+	Roots   []string `json:"roots"`
+	Watches int      `json:"watches"`
+}
+
+// Listen serves the control socket on name until the Watcher is closed.
+func (w *Watcher) Listen(name string) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o777); err != nil {
+		return err
+	}
+	if _, err := os.Lstat(name); err == nil {
+		if c, err := net.Dial("unix", name); err == nil {
+			c.Close()
+			return fmt.Errorf("%v: address already in use", name)
+		}
+		// stale socket
+		os.Remove(name)
+	}
+	ln, err := net.Listen("unix", name)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	w.ln = ln
+	w.mu.Unlock()
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go w.serve(c)
+		}
+	}()
+	return nil
+}
+
+func (w *Watcher) serve(c net.Conn) {
+	defer c.Close()
+
+	dec := json.NewDecoder(c)
+	enc := json.NewEncoder(c)
+	for {
+		var req Request
+		if err := dec.Decode(&req); err != nil {
+			return
+		}
+		if err := enc.Encode(w.control(&req)); err != nil {
+			return
+		}
+	}
+}
+
+func (w *Watcher) control(req *Request) *Response {
+	resp := new(Response)
+	switch req.Command {
+	case "status":
+		w.mu.Lock()
+		roots := w.roots
+		w.mu.Unlock()
+		resp.Status = &Status{
+			PID:     os.Getpid(),
+			Paused:  w.Paused(),
+			Roots:   roots,
+			Watches: w.a.numWatches(),
+		}
+	case "trigger":
+		if len(req.Files) == 0 {
+			resp.Error = "no files"
+			break
+		}
+		files := make([]string, len(req.Files))
+		for i, n := range req.Files {
+			files[i] = relPath(n)
+			if filepath.IsAbs(files[i]) || files[i] == ".." || strings.HasPrefix(files[i], ".."+string(os.PathSeparator)) {
+				resp.Error = fmt.Sprintf("%v: outside the tree", n)
+				break
+			}
+		}
+		if resp.Error == "" {
+			w.Trigger(files...)
+		}
+	case "reload":
+		w.Reload()
+	case "run-all":
+		w.RunAll()
+	case "pause":
+		w.Pause()
+	case "resume":
+		w.Resume()
+	default:
+		resp.Error = fmt.Sprintf("unknown command %q", req.Command)
+	}
+	return resp
+}
+
+// Control sends req to the control socket on name, and returns its response.
+func Control(name string, req *Request) (*Response, error) {
+	c, err := net.Dial("unix", name)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	if err := json.NewEncoder(c).Encode(req); err != nil {
+		return nil, err
+	}
+	resp := new(Response)
+	if err := json.NewDecoder(c).Decode(resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != "" {
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
@@ -0,0 +1,101 @@
+//
+// aster :: control_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hattya/aster"
+	"github.com/hattya/go.cli"
+)
+
+func TestControl(t *testing.T) {
+	at := &asterTest{
+		src: cli.Dedent(`
+			aster.watch(/.+\.go$/, function(files) {
+			  cycles.push(files.sort());
+			});
+		`),
+		before: func(a *aster.Aster, _ context.CancelFunc) {
+			a.Eval(`var cycles = [];`)
+		},
+		test: func(time.Duration, context.CancelFunc) {},
+		after: func(a *aster.Aster, w *aster.Watcher) {
+			if err := w.Listen(aster.ControlSocket); err != nil {
+				t.Fatal(err)
+			}
+			// in use
+			if err := w.Listen(aster.ControlSocket); err == nil {
+				t.Error("expected error")
+			}
+
+			control := func(cmd string, files ...string) *aster.Response {
+				resp, err := aster.Control(aster.ControlSocket, &aster.Request{
+					Command: cmd,
+					Files:   files,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				return resp
+			}
+			// status
+			resp := control("status")
+			if g, e := resp.Status, (&aster.Status{
+				PID:     os.Getpid(),
+				Roots:   []string{"."},
+				Watches: 1,
+			}); !reflect.DeepEqual(g, e) {
+				t.Errorf("expected %#v, got %#v", e, g)
+			}
+			// pause
+			control("pause")
+			if !control("status").Status.Paused {
+				t.Error("expected to be paused")
+			}
+			// trigger
+			b, _ := filepath.Abs("b.go")
+			control("trigger", "a.go", b, "c.txt")
+			time.Sleep(101 * time.Millisecond)
+			v, _ := a.Eval(`cycles.join(';');`)
+			if g, e := v.String(), ""; g != e {
+				t.Errorf("expected %q, got %q", e, g)
+			}
+			// resume
+			control("resume")
+			time.Sleep(101 * time.Millisecond)
+			v, _ = a.Eval(`cycles.join(';');`)
+			if g, e := v.String(), "a.go,b.go"; g != e {
+				t.Errorf("expected %q, got %q", e, g)
+			}
+			// errors
+			for _, req := range []*aster.Request{
+				{Command: "trigger"},
+				{Command: "trigger", Files: []string{filepath.Join("..", "a.go")}},
+				{Command: "unknown"},
+			} {
+				if _, err := aster.Control(aster.ControlSocket, req); err == nil {
+					t.Errorf("%v: expected error", req.Command)
+				}
+			}
+		},
+	}
+	stderr, err := at.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, e := stderr, ""; g != e {
+		t.Errorf("expected %q, got %q", e, g)
+	}
+}
@@ -0,0 +1,147 @@
+//
+// aster :: digest.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster
+
+import (
+	"container/list"
+	"os"
+	"sync"
+	"time"
+)
+
+// digests is an LRU cache of the digests of files.
+type digests struct {
+	mu      sync.Mutex
+	max     int
+	l       *list.List
+	m       map[string]*list.Element
+	touched map[string]struct{} // names which have been changed while priming
+}
+
+type digestEntry struct {
+	name string
+	sum  string
+	mode os.FileMode
+}
+
+func newDigests(max int) *digests {
+	return &digests{
+		max:     max,
+		l:       list.New(),
+		m:       make(map[string]*list.Element),
+		touched: make(map[string]struct{}),
+	}
+}
+
+// changed reports whether the contents or mode of name have been changed
+// since the last call, and records them.
+func (c *digests) changed(name string, fi os.FileInfo) bool {
+	sum, err := digest(name)
+	if err != nil {
+		return true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.m[name]; ok {
+		de := e.Value.(*digestEntry)
+		c.l.MoveToFront(e)
+		if de.sum == sum && de.mode == fi.Mode() {
+			return false
+		}
+		de.sum = sum
+		de.mode = fi.Mode()
+		return true
+	}
+	c.add(&digestEntry{
+		name: name,
+		sum:  sum,
+		mode: fi.Mode(),
+	})
+	return true
+}
+
+// touch marks name as changed, so that prime does not record it.
+func (c *digests) touch(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.touched != nil {
+		c.touched[name] = struct{}{}
+	}
+}
+
+// remove evicts name from the cache.
+func (c *digests) remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.m[name]; ok {
+		delete(c.m, name)
+		c.l.Remove(e)
+	}
+}
+
+// prime records the digest of name if it is not cached, and it was last
+// modified before since. stat is used to get the FileInfo of name.
+func (c *digests) prime(name string, since time.Time, stat func(string) (os.FileInfo, error)) {
+	if c.skip(name) {
+		return
+	}
+
+	sum, err := digest(name)
+	if err != nil {
+		return
+	}
+	// name may have been written while reading it
+	fi, err := stat(name)
+	if err != nil || !fi.ModTime().Before(since) {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.touched[name]; !ok {
+		if _, ok := c.m[name]; !ok {
+			c.add(&digestEntry{
+				name: name,
+				sum:  sum,
+				mode: fi.Mode(),
+			})
+		}
+	}
+}
+
+// skip reports whether prime does not need to record name.
+func (c *digests) skip(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.m[name]
+	_, touched := c.touched[name]
+	return ok || touched || c.l.Len() >= c.max
+}
+
+// primed stops tracking the names which are changed while priming.
+func (c *digests) primed() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.touched = nil
+}
+
+func (c *digests) add(de *digestEntry) {
+	c.m[de.name] = c.l.PushFront(de)
+	for c.l.Len() > c.max {
+		e := c.l.Back()
+		delete(c.m, e.Value.(*digestEntry).name)
+		c.l.Remove(e)
+	}
+}
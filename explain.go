@@ -0,0 +1,122 @@
+//
+// aster :: explain.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hattya/go.cli"
+)
+
+// Explanation describes how a path is handled.
+type Explanation struct {
+	Path    string
+	Dir     bool
+	Root    string   // empty if it is not under a watched directory
+	Ignore  string   // pattern which excludes it
+	Watches []string // patterns which match it in declaration order
+}
+
+// Explain evaluates the Asterfile like Check, and explains how each path is
+// handled.
+func Explain(ui *cli.CLI, paths []string, opts ...Option) ([]*Explanation, error) {
+	a, err := load(ui, "Asterfile", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	var list []*Explanation
+	for _, p := range paths {
+		if filepath.IsAbs(p) {
+			if rel, err := filepath.Rel(wd, p); err == nil {
+				p = rel
+			}
+		}
+		p = filepath.Clean(p)
+		e := &Explanation{Path: p}
+		if fi, err := os.Stat(p); err == nil {
+			e.Dir = fi.IsDir()
+		}
+		list = append(list, e)
+		// root
+		if !filepath.IsAbs(p) && p != ".." && !strings.HasPrefix(p, ".."+string(os.PathSeparator)) {
+			e.Root = "."
+		}
+		for _, r := range a.Roots() {
+			if r != "." && within(p, r) && (e.Root == "" || e.Root == "." || len(r) > len(e.Root)) {
+				e.Root = r
+			}
+		}
+		if e.Root == "" {
+			continue
+		}
+		// aster.ignore
+		rel := p
+		if e.Root != "." {
+			rel, _ = filepath.Rel(e.Root, p)
+		}
+		e.Ignore = a.explainIgnore(e.Root, rel, e.Dir)
+		// aster.watch
+		for _, w := range a.watches {
+			if e.Dir && !w.dirs {
+				continue
+			}
+			if v, _ := w.rx.Call("test", p); v.IsBoolean() {
+				if b, _ := v.ToBoolean(); b {
+					s := w.rx.Value().String()
+					switch {
+					case w.builtin:
+						s += " (builtin)"
+					case w.loc != "":
+						s += " (" + w.loc + ")"
+					}
+					e.Watches = append(e.Watches, s)
+				}
+			}
+		}
+	}
+	return list, nil
+}
+
+// explainIgnore returns the pattern which excludes rel or its parent
+// directories under root.
+func (a *Aster) explainIgnore(root, rel string, dir bool) string {
+	if rel == "." {
+		return ""
+	}
+	var vi *vcsIgnore
+	if a.IgnoreVCS() {
+		vi = newVCSIgnore(root)
+	}
+	elems := strings.Split(rel, string(os.PathSeparator))
+	for i := range elems {
+		name := filepath.Join(elems[:i+1]...)
+		isDir := dir || i < len(elems)-1
+		if rx := a.test(a.rx.ignore.Load(), name); rx != "" {
+			return rx
+		}
+		if !isDir {
+			if rx := a.test(a.rx.temp.Load(), filepath.Base(name)); rx != "" {
+				return rx
+			}
+		}
+		if vi != nil {
+			if rule := vi.Rule(name, isDir); rule != "" {
+				return rule
+			}
+		}
+	}
+	return ""
+}
@@ -1,34 +1,65 @@
 //
 // aster :: export_test.go
 //
-//   Copyright (c) 2017-2020 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2017-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
 
 package aster
 
-import "sort"
+import (
+	"os"
+	"sort"
+
+	"github.com/hattya/otto.module"
+)
 
 var (
-	NewBuffer = newBuffer
-	NewVM     = newVM
+	NewBuffer    = newBuffer
+	NewDigests   = newDigests
+	NewVCSIgnore = newVCSIgnore
+	Translate    = translate
 )
 
+func NewVM() *module.Otto {
+	return newVM(nil)
+}
+
+func (a *Aster) Set(name string, value any) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.vm.Set(name, value)
+}
+
 func (a *Aster) NumWatches() int {
 	return len(a.watches)
 }
 
+func (c *digests) Changed(name string) bool {
+	fi, err := os.Lstat(name)
+	if err != nil {
+		return true
+	}
+	return c.changed(name, fi)
+}
+
+func (c *digests) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.l.Len()
+}
+
 func (w *Watcher) Paths() []string {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	paths := make(sort.StringSlice, len(w.paths))
-	i := 0
-	for k := range w.paths {
-		paths[i] = k
-		i++
-	}
+	var paths sort.StringSlice
+	w.paths.walk(func(s string) {
+		paths = append(paths, s)
+	})
 	paths.Sort()
 	return paths
 }
@@ -0,0 +1,165 @@
+//
+// aster :: feedback.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robertkrimen/otto"
+)
+
+// timestamps of files can be coarser than the clock
+const granularity = 10 * time.Millisecond
+
+// span is a period of time.
+type span struct {
+	start time.Time
+	end   time.Time
+}
+
+// contains reports whether t is in the span.
+func (s span) contains(t time.Time) bool {
+	return !t.Before(s.start.Add(-granularity)) && !t.After(s.end)
+}
+
+// wrote records that name has been written by the running callback.
+func (a *Aster) wrote(name string) {
+	if a == nil || a.cur == nil {
+		return
+	}
+	if a.cur.written == nil {
+		a.cur.written = make(map[string]bool)
+	}
+	a.cur.written[relPath(name)] = true
+}
+
+// ran records that a child process has run by the running callback. Files
+// which are modified while it is running are treated as written.
+func (a *Aster) ran(start, end time.Time) {
+	if a == nil || a.cur == nil {
+		return
+	}
+	a.cur.children = append(a.cur.children, span{start, end})
+}
+
+// wrote reports whether name has been written by the previous callback of w,
+// and it has not been modified since then.
+func (w *watch) wrote(name string) bool {
+	if w.start.IsZero() {
+		return false
+	}
+	fi, err := os.Lstat(strings.TrimSuffix(name, string(os.PathSeparator)))
+	if err != nil || !(span{w.start, w.end}).contains(fi.ModTime()) {
+		return false
+	}
+	if w.written[name] || w.writes(name) {
+		return true
+	}
+	for _, s := range w.children {
+		if s.contains(fi.ModTime()) {
+			return true
+		}
+	}
+	return false
+}
+
+// writes reports whether name is matched to the outputs option of w.
+func (w *watch) writes(name string) bool {
+	if w.outputs == nil {
+		return false
+	}
+	v, _ := w.outputs.Get("length")
+	n, _ := v.ToInteger()
+	for i := range n {
+		switch v, _ := w.outputs.Get(strconv.FormatInt(i, 10)); {
+		case v.IsString():
+			if relPath(v.String()) == name {
+				return true
+			}
+		case v.Class() == "RegExp":
+			v, _ = v.Object().Call("test", name)
+			if b, _ := v.ToBoolean(); b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// outputList returns the outputs option as strings.
+func (w *watch) outputList() []string {
+	if w.outputs == nil {
+		return nil
+	}
+	var list []string
+	v, _ := w.outputs.Get("length")
+	n, _ := v.ToInteger()
+	for i := range n {
+		switch v, _ := w.outputs.Get(strconv.FormatInt(i, 10)); {
+		case v.IsString():
+			list = append(list, relPath(v.String()))
+		case v.Class() == "RegExp":
+			list = append(list, v.String())
+		}
+	}
+	return list
+}
+
+// triggered reports whether any of files has been modified while the previous
+// callback of w was running.
+func (w *watch) triggered(files []any) bool {
+	if w.start.IsZero() {
+		return false
+	}
+	for _, v := range files {
+		n, ok := v.(string)
+		if !ok {
+			continue
+		}
+		fi, err := os.Lstat(strings.TrimSuffix(n, string(os.PathSeparator)))
+		if err == nil && (span{w.start, w.end}).contains(fi.ModTime()) {
+			return true
+		}
+	}
+	return false
+}
+
+// loop counts how many times w has triggered itself in a row, and warns when
+// it exceeds aster.loopLimit.
+func (a *Aster) loop(w *watch, self bool) {
+	if !self {
+		w.loops = 0
+		return
+	}
+	w.loops++
+	v, _ := a.vm.Run(`aster.loopLimit`)
+	if n, _ := v.ToInteger(); n > 0 && int64(w.loops) == n+1 {
+		s := w.rx.Value().String()
+		if w.loc != "" {
+			s += " (" + w.loc + ")"
+		}
+		warn(a.ui, fmt.Sprintf("aster.watch: %v has triggered itself %v times in a row", s, w.loops))
+	}
+}
+
+// outputsOf returns the outputs option as an Array.
+func outputsOf(vm *otto.Otto, v otto.Value) *otto.Object {
+	switch {
+	case v.Class() == "Array":
+		return v.Object()
+	case v.IsString() || v.Class() == "RegExp":
+		ary, _ := vm.Call(`new Array`, nil, v)
+		return ary.Object()
+	}
+	return nil
+}
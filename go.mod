@@ -10,13 +10,13 @@ require (
 	github.com/hattya/otto.module v0.0.0-20250820130758-f4d92bd83107
 	github.com/robertkrimen/otto v0.5.1
 	github.com/saracen/walker v0.1.4
+	golang.org/x/sys v0.35.0
+	golang.org/x/term v0.34.0
 )
 
 require (
 	github.com/godbus/dbus/v5 v5.2.2 // indirect
 	golang.org/x/sync v0.6.0 // indirect
-	golang.org/x/sys v0.35.0 // indirect
-	golang.org/x/term v0.34.0 // indirect
 	golang.org/x/text v0.13.0 // indirect
 	gopkg.in/sourcemap.v1 v1.0.5 // indirect
 )
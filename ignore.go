@@ -0,0 +1,495 @@
+//
+// aster :: ignore.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/robertkrimen/otto"
+)
+
+// patterns is an Array of RegExp which is translated into Go.
+type patterns struct {
+	key []string
+	rx  []*regexp.Regexp
+	src []string       // source of rx
+	js  []*otto.Object // untranslatable RegExp
+}
+
+func newPatterns(ary *otto.Object) *patterns {
+	p := new(patterns)
+	if ary == nil || ary.Class() != "Array" {
+		return p
+	}
+
+	v, _ := ary.Get("length")
+	n, _ := v.ToInteger()
+	for i := range n {
+		v, _ := ary.Get(strconv.FormatInt(i, 10))
+		if v.Class() != "RegExp" {
+			continue
+		}
+		o := v.Object()
+		src, flags := regexpOf(o)
+		p.key = append(p.key, "/"+src+"/"+flags)
+		if rx := translate(src, flags); rx != nil {
+			p.rx = append(p.rx, rx)
+			p.src = append(p.src, p.key[len(p.key)-1])
+		} else {
+			p.js = append(p.js, o)
+		}
+	}
+	return p
+}
+
+func (p *patterns) equal(q *patterns) bool {
+	return p != nil && q != nil && slices.Equal(p.key, q.key)
+}
+
+func regexpOf(o *otto.Object) (src, flags string) {
+	v, _ := o.Get("source")
+	src, _ = v.ToString()
+	for _, f := range []struct {
+		k string
+		c string
+	}{
+		{"global", "g"},
+		{"ignoreCase", "i"},
+		{"multiline", "m"},
+	} {
+		if v, _ := o.Get(f.k); v.IsBoolean() {
+			if b, _ := v.ToBoolean(); b {
+				flags += f.c
+			}
+		}
+	}
+	return
+}
+
+// translate translates a RegExp into Go. It returns nil if fails.
+func translate(src, flags string) *regexp.Regexp {
+	var b strings.Builder
+	for _, c := range flags {
+		switch c {
+		case 'i', 'm':
+			if b.Len() == 0 {
+				b.WriteString("(?")
+			}
+			b.WriteRune(c)
+		}
+	}
+	if b.Len() > 0 {
+		b.WriteRune(')')
+	}
+	// JavaScript specific escapes
+	for i := 0; i < len(src); i++ {
+		if src[i] == '\\' && i+1 < len(src) {
+			switch src[i+1] {
+			case 'c', 'u', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+				return nil
+			case 'A', 'z', 'Z', 'Q', 'E', 'p', 'P':
+				// identity escapes in JavaScript
+				return nil
+			}
+			i++
+		}
+	}
+	b.WriteString(src)
+	rx, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil
+	}
+	return rx
+}
+
+// vcsIgnore reports whether a path is ignored by .gitignore or .hgignore
+// files under root.
+type vcsIgnore struct {
+	root string
+
+	mu      sync.Mutex
+	loaded  bool
+	exclude []*ignoreRule
+	hg      []*ignoreRule
+	git     map[string][]*ignoreRule
+}
+
+type ignoreRule struct {
+	src    string // <file>:<line>: <pattern>
+	rx     *regexp.Regexp
+	negate bool
+	dir    bool
+}
+
+func newVCSIgnore(root string) *vcsIgnore {
+	return &vcsIgnore{
+		root: root,
+		git:  make(map[string][]*ignoreRule),
+	}
+}
+
+func (vi *vcsIgnore) Reset() {
+	vi.mu.Lock()
+	defer vi.mu.Unlock()
+
+	vi.loaded = false
+	vi.exclude = nil
+	vi.hg = nil
+	clear(vi.git)
+}
+
+// Match reports whether name, which is relative to the root, is ignored.
+// A path is also ignored when any of its parent directories is ignored.
+func (vi *vcsIgnore) Match(name string, dir bool) bool {
+	return vi.Rule(name, dir) != ""
+}
+
+// Rule returns the rule which ignores name in the form of
+// "<file>:<line>: <pattern>". It returns an empty string if name is not
+// ignored.
+func (vi *vcsIgnore) Rule(name string, dir bool) string {
+	name = filepath.ToSlash(name)
+	if name == "." || name == "" {
+		return ""
+	}
+
+	for i := 0; ; {
+		j := strings.IndexByte(name[i:], '/')
+		if j == -1 {
+			if r := vi.match(name, dir); r != nil {
+				return r.src
+			}
+			return ""
+		}
+		i += j
+		if r := vi.match(name[:i], true); r != nil {
+			return r.src
+		}
+		i++
+	}
+}
+
+func (vi *vcsIgnore) match(name string, dir bool) *ignoreRule {
+	vi.mu.Lock()
+	defer vi.mu.Unlock()
+
+	if !vi.loaded {
+		vi.load()
+	}
+	// .hgignore
+	for _, r := range vi.hg {
+		if r.rx.MatchString(name) {
+			return r
+		}
+	}
+	// .gitignore
+	for d := name; d != ""; {
+		if i := strings.LastIndexByte(d, '/'); i != -1 {
+			d = d[:i]
+		} else {
+			d = ""
+		}
+		rules, ok := vi.git[d]
+		if !ok {
+			rules = vi.parse(filepath.Join(vi.root, filepath.FromSlash(d), ".gitignore"), parseGitignore)
+			vi.git[d] = rules
+		}
+		rel := name
+		if d != "" {
+			rel = name[len(d)+1:]
+		}
+		if r := lastMatch(rules, rel, dir); r != nil {
+			if r.negate {
+				return nil
+			}
+			return r
+		}
+	}
+	// .git/info/exclude
+	if r := lastMatch(vi.exclude, name, dir); r != nil && !r.negate {
+		return r
+	}
+	return nil
+}
+
+func (vi *vcsIgnore) load() {
+	vi.exclude = vi.parse(filepath.Join(vi.root, ".git", "info", "exclude"), parseGitignore)
+	vi.hg = vi.parse(filepath.Join(vi.root, ".hgignore"), parseHgignore)
+	vi.loaded = true
+}
+
+func (vi *vcsIgnore) parse(name string, fn func(*os.File) []*ignoreRule) []*ignoreRule {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	rules := fn(f)
+	if rel, err := filepath.Rel(vi.root, name); err == nil {
+		name = rel
+	}
+	for _, r := range rules {
+		r.src = name + ":" + r.src
+	}
+	return rules
+}
+
+func lastMatch(rules []*ignoreRule, name string, dir bool) *ignoreRule {
+	for i := len(rules) - 1; i >= 0; i-- {
+		r := rules[i]
+		if (!r.dir || dir) && r.rx.MatchString(name) {
+			return r
+		}
+	}
+	return nil
+}
+
+func parseGitignore(f *os.File) (rules []*ignoreRule) {
+	s := bufio.NewScanner(f)
+	for n := 1; s.Scan(); n++ {
+		if r := compileGitignore(s.Text()); r != nil {
+			r.src = strconv.Itoa(n) + ": " + strings.TrimSpace(s.Text())
+			rules = append(rules, r)
+		}
+	}
+	return
+}
+
+func compileGitignore(line string) *ignoreRule {
+	line = strings.TrimSuffix(line, "\r")
+	// trailing spaces
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	if line == "" || line[0] == '#' {
+		return nil
+	}
+
+	r := new(ignoreRule)
+	if line[0] == '!' {
+		r.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		r.dir = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteRune('^')
+	// a pattern without a slash matches at any level
+	if !strings.Contains(line, "/") {
+		b.WriteString(`(?:.*/)?`)
+	}
+	line = strings.TrimPrefix(line, "/")
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; c {
+		case '*':
+			if strings.HasPrefix(line[i:], "**") && (i == 0 || line[i-1] == '/') {
+				switch {
+				case i+2 == len(line):
+					b.WriteString(`.*`)
+					i++
+					continue
+				case line[i+2] == '/':
+					b.WriteString(`(?:.*/)?`)
+					i += 2
+					continue
+				}
+			}
+			b.WriteString(`[^/]*`)
+		case '?':
+			b.WriteString(`[^/]`)
+		case '[':
+			if n := writeClass(&b, line[i:]); n > 0 {
+				i += n - 1
+			} else {
+				b.WriteString(`\[`)
+			}
+		case '\\':
+			if i+1 < len(line) {
+				i++
+				c = line[i]
+			}
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteRune('$')
+
+	rx, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil
+	}
+	r.rx = rx
+	return r
+}
+
+func parseHgignore(f *os.File) (rules []*ignoreRule) {
+	syntax := "relre"
+	s := bufio.NewScanner(f)
+	for n := 1; s.Scan(); n++ {
+		line := s.Text()
+		// comment
+		for i := 0; i < len(line); i++ {
+			if line[i] == '#' && (i == 0 || line[i-1] != '\\') {
+				line = line[:i]
+				break
+			}
+		}
+		line = strings.TrimRight(strings.ReplaceAll(line, `\#`, "#"), " \t\r")
+		if line == "" {
+			continue
+		}
+
+		if v, ok := strings.CutPrefix(line, "syntax:"); ok {
+			switch v = strings.TrimSpace(v); v {
+			case "re", "regexp":
+				syntax = "relre"
+			case "glob":
+				syntax = "relglob"
+			default:
+				syntax = v
+			}
+			continue
+		}
+		kind := syntax
+		if i := strings.IndexByte(line, ':'); i != -1 {
+			switch k := line[:i]; k {
+			case "re", "regexp", "relre":
+				kind, line = "relre", line[i+1:]
+			case "glob", "relglob":
+				kind, line = "relglob", line[i+1:]
+			case "rootglob":
+				kind, line = k, line[i+1:]
+			}
+		}
+
+		var pat string
+		switch kind {
+		case "relre":
+			pat = line
+		case "relglob":
+			pat = `(?:^|/)` + hgGlob(line) + `(?:/|$)`
+		case "rootglob":
+			pat = `^` + hgGlob(line) + `(?:/|$)`
+		default:
+			continue
+		}
+		if rx, err := regexp.Compile(pat); err == nil {
+			rules = append(rules, &ignoreRule{
+				src: strconv.Itoa(n) + ": " + strings.TrimSpace(s.Text()),
+				rx:  rx,
+			})
+		}
+	}
+	return
+}
+
+func hgGlob(pat string) string {
+	var b strings.Builder
+	group := 0
+	for i := 0; i < len(pat); i++ {
+		switch c := pat[i]; c {
+		case '*':
+			if strings.HasPrefix(pat[i:], "**") {
+				b.WriteString(`.*`)
+				i++
+			} else {
+				b.WriteString(`[^/]*`)
+			}
+		case '?':
+			b.WriteString(`[^/]`)
+		case '[':
+			if n := writeClass(&b, pat[i:]); n > 0 {
+				i += n - 1
+			} else {
+				b.WriteString(`\[`)
+			}
+		case '{':
+			group++
+			b.WriteString(`(?:`)
+		case ',':
+			if group > 0 {
+				b.WriteRune('|')
+			} else {
+				b.WriteRune(',')
+			}
+		case '}':
+			if group > 0 {
+				group--
+				b.WriteRune(')')
+			} else {
+				b.WriteString(`\}`)
+			}
+		case '\\':
+			if i+1 < len(pat) {
+				i++
+				c = pat[i]
+			}
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// writeClass writes a bracket expression at the beginning of s, and returns
+// its length. It returns 0 if s does not start with a valid bracket
+// expression.
+func writeClass(b *strings.Builder, s string) int {
+	i := 1
+	if i < len(s) && (s[i] == '!' || s[i] == '^') {
+		i++
+	}
+	if i < len(s) && s[i] == ']' {
+		i++
+	}
+	j := strings.IndexByte(s[i:], ']')
+	if j == -1 {
+		return 0
+	}
+	j += i
+
+	b.WriteRune('[')
+	i = 1
+	if s[i] == '!' || s[i] == '^' {
+		b.WriteRune('^')
+		i++
+	}
+	for ; i < j; i++ {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 < j {
+				i++
+			}
+			b.WriteString(regexp.QuoteMeta(string(s[i])))
+		case '[', ']', '^':
+			b.WriteRune('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteRune(']')
+	return j + 1
+}
@@ -0,0 +1,186 @@
+//
+// aster :: ignore_test.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hattya/aster"
+	"github.com/hattya/aster/internal/sh"
+	"github.com/hattya/aster/internal/test"
+	"github.com/hattya/go.cli"
+)
+
+var translateTests = []struct {
+	src string
+	ok  bool
+}{
+	{`.+\.go$`, true},
+	{`^a\d\s\w\b`, true},
+	{`\\u`, true},
+	{`\u0041`, false},
+	{`\cJ`, false},
+	{`(a)\1`, false},
+	{`\0`, false},
+	{`\Afoo`, false},
+	{`foo\z`, false},
+	{`foo\Z`, false},
+	{`\Qfoo`, false},
+	{`foo\E`, false},
+	{`\p{L}`, false},
+	{`\P{L}`, false},
+}
+
+func TestTranslate(t *testing.T) {
+	for _, tt := range translateTests {
+		if g, e := aster.Translate(tt.src, "") != nil, tt.ok; g != e {
+			t.Errorf("translate(%q) != nil = %v, expected %v", tt.src, g, e)
+		}
+	}
+}
+
+var gitignoreTests = []struct {
+	name    string
+	dir     bool
+	ignored bool
+}{
+	{"a.o", false, true},
+	{"lib/a.o", false, true},
+	{"keep.o", false, false},
+	{"lib/keep.o", false, false},
+	{"build", true, true},
+	{"build/a.go", false, true},
+	{"src/build", false, false},
+	{"src/build", true, true},
+	{"root.txt", false, true},
+	{"src/root.txt", false, false},
+	{"doc/a/b/c.tmp", false, true},
+	{"doc/c.tmp", false, true},
+	{"x.tmp", false, false},
+	{"a.go", false, false},
+	{"sub/a.go", false, true},
+	{"sub/b.go", false, false},
+	{"sub/local", true, true},
+	{"local", true, false},
+	{"#hash", false, true},
+	{"!bang", false, true},
+	{"a.swp", false, true},
+	{"a.swx", false, false},
+	{"secret.txt", false, true},
+}
+
+func TestGitignore(t *testing.T) {
+	err := test.Sandbox(func() error {
+		if err := sh.Mkdir(".git", "info"); err != nil {
+			return err
+		}
+		if err := sh.Mkdir("sub"); err != nil {
+			return err
+		}
+		for name, src := range map[string]string{
+			".gitignore": cli.Dedent(`
+				# comment
+				*.o
+				!keep.o
+				build/
+				/root.txt
+				doc/**/*.tmp
+				\#hash
+				\!bang
+				*.sw[a-p]   
+			`),
+			filepath.Join("sub", ".gitignore"): cli.Dedent(`
+				a.go
+				/local/
+			`),
+			filepath.Join(".git", "info", "exclude"): cli.Dedent(`
+				secret.txt
+			`),
+		} {
+			if err := os.WriteFile(name, []byte(src), 0o666); err != nil {
+				return err
+			}
+		}
+
+		vi := aster.NewVCSIgnore(".")
+		for _, tt := range gitignoreTests {
+			if g, e := vi.Match(filepath.FromSlash(tt.name), tt.dir), tt.ignored; g != e {
+				t.Errorf("Match(%q, %v) = %v, expected %v", tt.name, tt.dir, g, e)
+			}
+		}
+		for _, tt := range []struct {
+			name, rule string
+		}{
+			{"a.o", ".gitignore:2: *.o"},
+			{"keep.o", ""},
+			{"build/a.go", ".gitignore:4: build/"},
+			{"sub/a.go", filepath.Join("sub", ".gitignore") + ":1: a.go"},
+			{"secret.txt", filepath.Join(".git", "info", "exclude") + ":1: secret.txt"},
+		} {
+			if g, e := vi.Rule(filepath.FromSlash(tt.name), false), tt.rule; g != e {
+				t.Errorf("Rule(%q) = %q, expected %q", tt.name, g, e)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+var hgignoreTests = []struct {
+	name    string
+	ignored bool
+}{
+	{"a.pyc", true},
+	{"lib/a.pyc", true},
+	{"a.py", false},
+	{"build/a.py", true},
+	{"src/build/a.py", false},
+	{"a.orig", true},
+	{"lib/a.orig", true},
+	{"dist/a", true},
+	{"lib/dist/a", false},
+	{"a.c", true},
+	{"a.h", true},
+	{"a.go", false},
+}
+
+func TestHgignore(t *testing.T) {
+	err := test.Sandbox(func() error {
+		src := cli.Dedent(`
+			# comment
+			\.pyc$
+			^build/
+			syntax: glob
+			*.orig
+			rootglob:dist
+			*.{c,h}
+		`)
+		if err := os.WriteFile(".hgignore", []byte(src), 0o666); err != nil {
+			return err
+		}
+
+		vi := aster.NewVCSIgnore(".")
+		for _, tt := range hgignoreTests {
+			if g, e := vi.Match(filepath.FromSlash(tt.name), false), tt.ignored; g != e {
+				t.Errorf("Match(%q) = %v, expected %v", tt.name, g, e)
+			}
+		}
+		if g, e := vi.Rule("a.orig", false), ".hgignore:5: *.orig"; g != e {
+			t.Errorf("Rule(%q) = %q, expected %q", "a.orig", g, e)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
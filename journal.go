@@ -0,0 +1,174 @@
+//
+// aster :: journal.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"os/exec"
+	"slices"
+	"time"
+)
+
+// Record is a line of the JSON log, which is written for each cycle.
+type Record struct {
+	Time     time.Time       `json:"time"`
+	Type     string          `json:"type"` // "change" or "run-all"
+	Files    []string        `json:"files"`
+	Renames  []*RenameRecord `json:"renames,omitempty"`
+	Count    int             `json:"count"`
+	Duration float64         `json:"duration"` // in seconds
+	Watches  []*WatchRecord  `json:"watches"`
+
+	start time.Time
+}
+
+// WatchRecord is a record of a watch which consumed files.
+type WatchRecord struct {
+	Pattern       string           `json:"pattern"`
+	Files         []string         `json:"files"`
+	Renames       []*RenameRecord  `json:"renames,omitempty"`
+	Count         int              `json:"count"`
+	Duration      float64          `json:"duration"` // in seconds
+	Error         string           `json:"error,omitempty"`
+	Commands      []*CommandRecord `json:"commands"`
+	Notifications []*NotifyRecord  `json:"notifications"`
+}
+
+// RenameRecord is a record of a renamed file. Files also contain To.
+type RenameRecord struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// CommandRecord is a record of a command which is run by os.system.
+type CommandRecord struct {
+	Args     []string `json:"args"`
+	Dir      string   `json:"dir,omitempty"`
+	Exit     int      `json:"exit"`     // -1 if it did not exit normally
+	Duration float64  `json:"duration"` // in seconds
+	Error    string   `json:"error,omitempty"`
+}
+
+// NotifyRecord is a record of a notification which is sent by aster.notify.
+type NotifyRecord struct {
+	Name  string `json:"name"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Error string `json:"error,omitempty"`
+}
+
+// JSONLog writes a Record to w for each cycle.
+func JSONLog(w io.Writer) Option {
+	return func(a *Aster) {
+		a.jl = w
+	}
+}
+
+// begin starts a record of the cycle. It returns nil if the JSON log is
+// disabled.
+func (a *Aster) begin(typ string, files iter.Seq[string], renames map[string]string) *Record {
+	if a.jl == nil {
+		return nil
+	}
+	now := time.Now()
+	r := &Record{
+		Time:    now,
+		Type:    typ,
+		Files:   slices.Sorted(files),
+		Watches: []*WatchRecord{},
+		start:   now,
+	}
+	r.Count = len(r.Files)
+	for _, n := range r.Files {
+		if from, ok := renames[n]; ok {
+			r.Renames = append(r.Renames, &RenameRecord{From: from, To: n})
+		}
+	}
+	return r
+}
+
+// end writes r to the JSON log.
+func (a *Aster) end(r *Record) {
+	if r == nil {
+		return
+	}
+	r.Duration = time.Since(r.start).Seconds()
+	b, err := json.Marshal(r)
+	if err != nil {
+		warn(a.ui, err)
+		return
+	}
+	if _, err := a.jl.Write(append(b, '\n')); err != nil {
+		warn(a.ui, err)
+	}
+}
+
+func (r *Record) watch(pattern string, files []string, renames []*RenameRecord) *WatchRecord {
+	if r == nil {
+		return nil
+	}
+	wr := &WatchRecord{
+		Pattern:       pattern,
+		Files:         files,
+		Renames:       renames,
+		Count:         len(files),
+		Commands:      []*CommandRecord{},
+		Notifications: []*NotifyRecord{},
+	}
+	r.Watches = append(r.Watches, wr)
+	return wr
+}
+
+func (wr *WatchRecord) done(start time.Time, err error) {
+	if wr == nil {
+		return
+	}
+	wr.Duration = time.Since(start).Seconds()
+	if err != nil {
+		wr.Error = err.Error()
+	}
+}
+
+func (wr *WatchRecord) command(cmd *exec.Cmd, start time.Time, err error) {
+	if wr == nil {
+		return
+	}
+	cr := &CommandRecord{
+		Args:     cmd.Args,
+		Dir:      cmd.Dir,
+		Exit:     -1,
+		Duration: time.Since(start).Seconds(),
+	}
+	if cmd.ProcessState != nil {
+		cr.Exit = cmd.ProcessState.ExitCode()
+	}
+	var ee *exec.ExitError
+	if err != nil && !errors.As(err, &ee) {
+		cr.Error = err.Error()
+	}
+	wr.Commands = append(wr.Commands, cr)
+}
+
+func (wr *WatchRecord) notify(args [3]string, err error) {
+	if wr == nil {
+		return
+	}
+	nr := &NotifyRecord{
+		Name:  args[0],
+		Title: args[1],
+		Body:  args[2],
+	}
+	if err != nil {
+		nr.Error = err.Error()
+	}
+	wr.Notifications = append(wr.Notifications, nr)
+}
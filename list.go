@@ -0,0 +1,93 @@
+//
+// aster :: list.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster
+
+import (
+	"os"
+	"strings"
+
+	"github.com/hattya/go.cli"
+)
+
+// WatchInfo describes a watch which is registered by aster.watch, or by Aster
+// itself to reload the Asterfile.
+type WatchInfo struct {
+	Pattern  string       `json:"pattern"`
+	Location string       `json:"location"`
+	Builtin  bool         `json:"builtin,omitempty"`
+	Options  WatchOptions `json:"options"`
+	Files    []string     `json:"files,omitempty"`
+}
+
+// WatchOptions is the options of aster.watch.
+type WatchOptions struct {
+	Dirs     bool     `json:"dirs"`
+	Renames  bool     `json:"renames"`
+	Debounce string   `json:"debounce,omitempty"`
+	Throttle string   `json:"throttle,omitempty"`
+	Leading  bool     `json:"leading"`
+	RunAll   bool     `json:"runAll"`
+	Outputs  []string `json:"outputs,omitempty"`
+}
+
+// List evaluates the Asterfile like Check, and returns the registered watches.
+// When files is true, it also returns the existing files which each watch
+// receives.
+func List(ui *cli.CLI, files bool, opts ...Option) ([]*WatchInfo, error) {
+	a, err := load(ui, "Asterfile", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*WatchInfo
+	var watches []*watch
+	for _, w := range a.watches {
+		wi := &WatchInfo{
+			Pattern:  w.rx.Value().String(),
+			Location: w.loc,
+			Builtin:  w.builtin,
+			Options: WatchOptions{
+				Dirs:    w.dirs,
+				Renames: w.renames,
+				Leading: w.leading,
+				RunAll:  w.all != nil,
+				Outputs: w.outputList(),
+			},
+		}
+		if w.debounce > 0 {
+			wi.Options.Debounce = w.debounce.String()
+		}
+		if w.throttle > 0 {
+			wi.Options.Throttle = w.throttle.String()
+		}
+		list = append(list, wi)
+		watches = append(watches, w)
+	}
+	if files {
+		tree, err := a.tree(".")
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range tree {
+			s, dir := strings.CutSuffix(n, string(os.PathSeparator))
+			for i, w := range watches {
+				if dir && !w.dirs {
+					continue
+				}
+				if v, _ := w.rx.Call("test", s); v.IsBoolean() {
+					if b, _ := v.ToBoolean(); b {
+						list[i].Files = append(list[i].Files, n)
+						break
+					}
+				}
+			}
+		}
+	}
+	return list, nil
+}
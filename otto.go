@@ -1,7 +1,7 @@
 //
 // aster :: otto.go
 //
-//   Copyright (c) 2014-2025 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -23,7 +23,7 @@ import (
 	"github.com/robertkrimen/otto"
 )
 
-func newVM() *module.Otto {
+func newVM(a *Aster) *module.Otto {
 	vm, err := module.New()
 	if err != nil {
 		panic(err)
@@ -44,7 +44,7 @@ func newVM() *module.Otto {
 		o.Set("MODE_TYPE", os.ModeType)
 		o.Set("MODE_PERM", os.ModePerm)
 
-		m := new(os_)
+		m := &os_{a: a}
 		o.Set("getwd", m.getwd)
 		o.Set("mkdir", m.mkdir)
 		o.Set("open", m.open)
@@ -61,6 +61,11 @@ func newVM() *module.Otto {
 }
 
 type os_ struct {
+	a *Aster
+}
+
+func (m *os_) dry() bool {
+	return m.a != nil && m.a.dry
 }
 
 func (*os_) getwd(call otto.FunctionCall) otto.Value {
@@ -69,7 +74,7 @@ func (*os_) getwd(call otto.FunctionCall) otto.Value {
 	return v
 }
 
-func (*os_) mkdir(call otto.FunctionCall) otto.Value {
+func (m *os_) mkdir(call otto.FunctionCall) otto.Value {
 	if len(call.ArgumentList) < 1 {
 		return otto.UndefinedValue()
 	}
@@ -83,13 +88,17 @@ func (*os_) mkdir(call otto.FunctionCall) otto.Value {
 	if perm == 0 {
 		perm = os.FileMode(0o777)
 	}
+	if m.dry() {
+		m.a.dryRun("mkdir %v", path)
+		return otto.UndefinedValue()
+	}
 	if os.MkdirAll(path, perm) != nil {
 		return otto.TrueValue()
 	}
 	return otto.UndefinedValue()
 }
 
-func (*os_) open(call otto.FunctionCall) otto.Value {
+func (m *os_) open(call otto.FunctionCall) otto.Value {
 	if len(call.ArgumentList) < 1 {
 		return otto.UndefinedValue()
 	}
@@ -111,35 +120,65 @@ func (*os_) open(call otto.FunctionCall) otto.Value {
 		flag = os.O_RDWR | os.O_CREATE | os.O_APPEND
 	}
 
-	f, err := os.OpenFile(name, flag, 0o666)
+	var f *os.File
+	var err error
+	dry := m.dry() && flag&(os.O_WRONLY|os.O_RDWR) != 0
+	if dry {
+		// discard writes
+		m.a.dryRun("open %v", name)
+		if _, err = os.Stat(name); flag&os.O_TRUNC != 0 || os.IsNotExist(err) {
+			f, err = os.Open(os.DevNull)
+		} else {
+			f, err = os.Open(name)
+		}
+	} else {
+		f, err = os.OpenFile(name, flag, 0o666)
+		if err == nil && flag&(os.O_WRONLY|os.O_RDWR) != 0 {
+			m.a.wrote(name)
+		}
+	}
 	if err != nil {
 		return module.Throw(call.Otto, err)
 	}
+	impl := newFile(call.Otto, f)
+	impl.name = name
+	if dry {
+		impl.w = discard
+	}
 	this := call.This.Object()
-	this.Set("_impl", newFile(call.Otto, f))
+	this.Set("_impl", impl)
 	return call.This
 }
 
-func (*os_) remove(call otto.FunctionCall) otto.Value {
+func (m *os_) remove(call otto.FunctionCall) otto.Value {
 	if len(call.ArgumentList) < 1 {
 		return otto.UndefinedValue()
 	}
 
 	path, _ := call.ArgumentList[0].ToString()
+	if m.dry() {
+		m.a.dryRun("remove %v", path)
+		return otto.UndefinedValue()
+	}
 	os.RemoveAll(path)
 	return otto.UndefinedValue()
 }
 
-func (*os_) rename(call otto.FunctionCall) otto.Value {
+func (m *os_) rename(call otto.FunctionCall) otto.Value {
 	if len(call.ArgumentList) < 2 {
 		return otto.UndefinedValue()
 	}
 
 	src, _ := call.ArgumentList[0].ToString()
 	dst, _ := call.ArgumentList[1].ToString()
+	if m.dry() {
+		m.a.dryRun("rename %v %v", src, dst)
+		return otto.UndefinedValue()
+	}
 	if os.Rename(src, dst) != nil {
 		return otto.TrueValue()
 	}
+	m.a.wrote(dst)
 	return otto.UndefinedValue()
 }
 
@@ -162,7 +201,7 @@ func (*os_) stat(call otto.FunctionCall) otto.Value {
 	return call.This
 }
 
-func (*os_) system(call otto.FunctionCall) otto.Value {
+func (m *os_) system(call otto.FunctionCall) otto.Value {
 	// defaults
 	var dir string
 	var stdout io.WriteCloser = os.Stdout
@@ -186,6 +225,19 @@ func (*os_) system(call otto.FunctionCall) otto.Value {
 	}
 	// options
 	v = call.Argument(1)
+	if m.dry() {
+		if v.Class() == "Object" {
+			if v, _ := v.Object().Get("dir"); v.IsString() {
+				dir = v.String()
+			}
+		}
+		if dir != "" {
+			m.a.dryRun("system %v (in %v)", quote(args), dir)
+		} else {
+			m.a.dryRun("system %v", quote(args))
+		}
+		return otto.UndefinedValue()
+	}
 	if v.Class() == "Object" {
 		options := v.Object()
 		// dir
@@ -198,7 +250,9 @@ func (*os_) system(call otto.FunctionCall) otto.Value {
 			switch v, _ = o.Get(k); {
 			case v.IsString():
 				s, _ := v.ToString()
-				w, err = os.Create(s)
+				if w, err = os.Create(s); err == nil {
+					m.a.wrote(s)
+				}
 			case v.IsNull():
 				w = discard
 			case v.Class() == "Array":
@@ -226,10 +280,23 @@ func (*os_) system(call otto.FunctionCall) otto.Value {
 
 	cmd := binfmt.Command(args[0], args[1:]...)
 	cmd.Dir = dir
-	cmd.Stdin = os.Stdin
+	if m.a == nil || !m.a.detach {
+		cmd.Stdin = os.Stdin
+	}
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
-	if err := cmd.Run(); err != nil {
+	// hand over the terminal
+	if m.a != nil && m.a.term != nil {
+		m.a.term.Suspend()
+		defer m.a.term.Resume()
+	}
+	start := time.Now()
+	err := m.run(cmd)
+	if m.a != nil {
+		m.a.ran(start, time.Now())
+		m.a.rec.command(cmd, start, err)
+	}
+	if err != nil {
 		if _, ok := err.(*exec.ExitError); ok {
 			return otto.TrueValue()
 		}
@@ -238,6 +305,18 @@ func (*os_) system(call otto.FunctionCall) otto.Value {
 	return otto.UndefinedValue()
 }
 
+func (m *os_) run(cmd *exec.Cmd) error {
+	if m.a == nil {
+		return cmd.Run()
+	}
+
+	if err := m.a.procs.start(cmd); err != nil {
+		return err
+	}
+	defer m.a.procs.done(cmd)
+	return cmd.Wait()
+}
+
 func (*os_) whence(call otto.FunctionCall) otto.Value {
 	if len(call.ArgumentList) < 1 {
 		return otto.UndefinedValue()
@@ -294,17 +373,21 @@ func (b *buffer) Close() error {
 }
 
 type file struct {
-	vm *otto.Otto
-	f  *os.File
+	vm   *otto.Otto
+	f    *os.File
+	name string
 
 	br *bufio.Reader
+	w  io.Writer
 }
 
 func newFile(vm *otto.Otto, f *os.File) *file {
 	return &file{
-		vm: vm,
-		f:  f,
-		br: bufio.NewReader(f),
+		vm:   vm,
+		f:    f,
+		name: f.Name(),
+		br:   bufio.NewReader(f),
+		w:    f,
 	}
 }
 
@@ -316,7 +399,7 @@ func (f *file) Close(call otto.FunctionCall) otto.Value {
 }
 
 func (f *file) Name(call otto.FunctionCall) otto.Value {
-	v, _ := call.Otto.ToValue(f.f.Name())
+	v, _ := call.Otto.ToValue(f.name)
 	return v
 }
 
@@ -351,7 +434,7 @@ func (f *file) ReadLine(call otto.FunctionCall) otto.Value {
 
 func (f *file) Write(call otto.FunctionCall) otto.Value {
 	v, _ := call.Argument(0).ToString()
-	if _, err := f.f.WriteString(v); err != nil {
+	if _, err := io.WriteString(f.w, v); err != nil {
 		return module.Throw(f.vm, err)
 	}
 	return otto.UndefinedValue()
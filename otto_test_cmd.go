@@ -1,7 +1,7 @@
 //
 // aster :: otto_test_cmd.go
 //
-//   Copyright (c) 2014-2025 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -14,14 +14,29 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 )
 
-var code int
+var (
+	code  int
+	sleep time.Duration
+	write string
+)
 
 func main() {
 	flag.IntVar(&code, "code", 0, "")
+	flag.DurationVar(&sleep, "sleep", 0, "")
+	flag.StringVar(&write, "write", "", "")
 	flag.Parse()
 
+	time.Sleep(sleep)
+	if write != "" {
+		if err := os.WriteFile(write, []byte(time.Now().String()), 0o666); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
 	if code == 0 {
 		fmt.Fprintln(os.Stdout, "stdout")
 	} else {
@@ -0,0 +1,60 @@
+//
+// aster :: proc.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster
+
+import (
+	"errors"
+	"os/exec"
+	"sync"
+)
+
+var errTerminated = errors.New("terminated")
+
+// procs is a set of running child processes.
+type procs struct {
+	mu         sync.Mutex
+	m          map[*exec.Cmd]struct{}
+	terminated bool
+}
+
+func (p *procs) start(cmd *exec.Cmd) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.terminated {
+		return errTerminated
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	if p.m == nil {
+		p.m = make(map[*exec.Cmd]struct{})
+	}
+	p.m[cmd] = struct{}{}
+	return nil
+}
+
+func (p *procs) done(cmd *exec.Cmd) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.m, cmd)
+}
+
+// terminate terminates the running child processes, and prevents starting
+// new ones.
+func (p *procs) terminate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.terminated = true
+	for cmd := range p.m {
+		terminate(cmd.Process)
+	}
+}
@@ -0,0 +1,20 @@
+//
+// aster :: proc_unix.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+//go:build unix
+
+package aster
+
+import (
+	"os"
+	"syscall"
+)
+
+func terminate(p *os.Process) error {
+	return p.Signal(syscall.SIGTERM)
+}
@@ -0,0 +1,15 @@
+//
+// aster :: proc_windows.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster
+
+import "os"
+
+func terminate(p *os.Process) error {
+	return p.Kill()
+}
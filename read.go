@@ -0,0 +1,158 @@
+//
+// aster :: read.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Event is a change event which is read by Watcher.Read.
+type Event struct {
+	Path string `json:"path"`
+	Op   string `json:"op,omitempty"`   // "create", "write", "remove" or "rename"
+	From string `json:"from,omitempty"` // old path of "rename"
+}
+
+// External reads changes by Watcher.Read instead of watching the file system.
+func External(ext bool) WatcherOption {
+	return func(w *Watcher) {
+		w.ext = ext
+	}
+}
+
+// Read reads changes from r instead of the file system events until EOF.
+// The pending files are processed at EOF, and then Watch returns.
+//
+// r is newline- or NUL-separated paths, or a line of JSON for each Event. A
+// path which does not exist is treated as removed, otherwise as written.
+func (w *Watcher) Read(r io.Reader) {
+	w.mu.Lock()
+	if !w.ext {
+		w.ext = true
+		for _, n := range w.w.WatchList() {
+			w.w.Remove(n)
+		}
+	}
+	w.mu.Unlock()
+
+	go func() {
+		s := bufio.NewScanner(r)
+		s.Split(scanPaths)
+		for s.Scan() {
+			l := strings.TrimSpace(s.Text())
+			if l == "" {
+				continue
+			}
+			evs, err := w.parse(l)
+			if err != nil {
+				warn(w.a.ui, err)
+				continue
+			}
+			for _, ev := range evs {
+				if w.ignoreParent(ev.Name) {
+					continue
+				}
+				select {
+				case w.events <- ev:
+				case <-w.done:
+					return
+				}
+			}
+		}
+		if err := s.Err(); err != nil {
+			warn(w.a.ui, err)
+		}
+		close(w.eof)
+	}()
+}
+
+// ignoreParent reports whether any parent directory of name is ignored. The
+// file system events are not reported under them.
+func (w *Watcher) ignoreParent(name string) bool {
+	for dir := filepath.Dir(name); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if w.ignore(dir, true) {
+			return true
+		}
+	}
+	return false
+}
+
+// parse converts a line into fsnotify events.
+func (w *Watcher) parse(l string) ([]fsnotify.Event, error) {
+	if !strings.HasPrefix(l, "{") {
+		name := relPath(l)
+		op := fsnotify.Write
+		if _, err := os.Lstat(name); err != nil {
+			op = fsnotify.Remove
+		}
+		return []fsnotify.Event{{Name: name, Op: op}}, nil
+	}
+
+	var ev Event
+	if err := json.Unmarshal([]byte(l), &ev); err != nil {
+		return nil, err
+	}
+	if ev.Path == "" {
+		return nil, fmt.Errorf("no path: %v", l)
+	}
+	name := relPath(ev.Path)
+	switch ev.Op {
+	case "", "write":
+		return []fsnotify.Event{{Name: name, Op: fsnotify.Write}}, nil
+	case "create":
+		return []fsnotify.Event{{Name: name, Op: fsnotify.Create}}, nil
+	case "remove":
+		return []fsnotify.Event{{Name: name, Op: fsnotify.Remove}}, nil
+	case "rename":
+		if ev.From == "" {
+			return []fsnotify.Event{{Name: name, Op: fsnotify.Create}}, nil
+		}
+		// renamed file is reported as Rename and Create
+		return []fsnotify.Event{
+			{Name: relPath(ev.From), Op: fsnotify.Rename},
+			{Name: name, Op: fsnotify.Create},
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown op %q", ev.Op)
+}
+
+// relPath returns name relative to the current directory.
+func relPath(name string) string {
+	if filepath.IsAbs(name) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, name); err == nil {
+				name = rel
+			}
+		}
+	}
+	return filepath.Clean(name)
+}
+
+// scanPaths is a bufio.SplitFunc which splits data by newline or NUL.
+func scanPaths(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\n\x00"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,74 @@
+//
+// aster :: run.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Run feeds files to the watches once like OnChange. When files is empty,
+// all of the files in the watched directories are fed. It returns an error if
+// a callback threw, or reported a failure by aster.notify.
+func (a *Aster) Run(ctx context.Context, files []string) error {
+	if len(files) == 0 {
+		tree, err := a.tree(".")
+		if err != nil {
+			return err
+		}
+		for _, n := range tree {
+			if !strings.HasSuffix(n, string(os.PathSeparator)) {
+				files = append(files, n)
+			}
+		}
+	}
+
+	m := make(map[string]int)
+	a.mu.Lock()
+	for _, n := range files {
+		n = filepath.Clean(n)
+		if !a.builtin(n) {
+			m[n]++
+		}
+	}
+	a.run = true
+	a.failures = 0
+	a.mu.Unlock()
+	if len(m) > 0 {
+		a.OnChange(ctx, m, nil)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.run = false
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case a.failures == 1:
+		return errors.New("1 failure")
+	case a.failures > 1:
+		return fmt.Errorf("%v failures", a.failures)
+	}
+	return nil
+}
+
+// builtin reports whether name is handled by the builtin watch.
+func (a *Aster) builtin(name string) bool {
+	for _, w := range a.watches {
+		if w.builtin && w.re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+//
+// aster :: schedule.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster
+
+import "time"
+
+// schedule represents when a batch of events is processed. The zero value
+// squashes events during Watcher.Squash from the first event.
+type schedule struct {
+	debounce time.Duration
+	throttle time.Duration
+	leading  bool
+}
+
+// delay returns the duration to wait after an event.
+func (s schedule) delay(squash time.Duration) time.Duration {
+	if s.debounce > 0 {
+		return s.debounce
+	}
+	return squash
+}
+
+// queue is a batch of events which is scheduled independently.
+type queue struct {
+	schedule
+
+	files   map[string]int
+	renames map[string]string
+	timer   *time.Timer
+	at      time.Time // when the timer fires
+	armed   bool
+	ready   bool
+	last    time.Time // when the last batch was processed
+}
+
+func newQueue(s schedule) *queue {
+	return &queue{
+		schedule: s,
+		files:    make(map[string]int),
+		renames:  make(map[string]string),
+	}
+}
+
+// idle reports whether the leading edge of the queue is open.
+func (q *queue) idle(squash time.Duration) bool {
+	return !q.armed && time.Since(q.last) >= max(q.delay(squash), q.throttle)
+}
+
+// arm starts or resets the timer. fn is called when it fires.
+func (q *queue) arm(squash time.Duration, fn func()) {
+	if q.armed && q.debounce == 0 {
+		// squash from the first event
+		return
+	}
+	d := q.delay(squash)
+	if t := time.Until(q.last.Add(q.throttle)); t > d {
+		d = t
+	}
+	q.at = time.Now().Add(d)
+	q.armed = true
+	if q.timer == nil {
+		q.timer = time.AfterFunc(d, fn)
+	} else {
+		q.timer.Reset(d)
+	}
+}
+
+// expire is called when the timer fires, and reports whether the queue is
+// ready to be processed.
+func (q *queue) expire() bool {
+	if !q.armed || time.Now().Before(q.at) {
+		// timer has been reset
+		return false
+	}
+	q.armed = false
+	q.ready = len(q.files) > 0
+	return q.ready
+}
+
+func (q *queue) stop() {
+	if q.timer != nil {
+		q.timer.Stop()
+	}
+	q.armed = false
+}
@@ -0,0 +1,152 @@
+//
+// aster :: state.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// StateFile is the path of the snapshot of files.
+var StateFile = filepath.Join(".aster", "state")
+
+type snapshot struct {
+	Version int                   `json:"version"`
+	Files   map[string]*fileState `json:"files"`
+}
+
+type fileState struct {
+	Size  int64     `json:"size"`
+	MTime time.Time `json:"mtime"`
+	Hash  string    `json:"hash,omitempty"`
+}
+
+// SaveState writes the snapshot of files to name. When hash is true, it also
+// records the digest of each file.
+func (w *Watcher) SaveState(name string, hash bool) error {
+	ss := &snapshot{
+		Version: 1,
+		Files:   make(map[string]*fileState),
+	}
+	for _, n := range w.files() {
+		fi, err := w.lstat(n)
+		if err != nil {
+			continue
+		}
+		st := &fileState{
+			Size:  fi.Size(),
+			MTime: fi.ModTime(),
+		}
+		if hash {
+			if st.Hash, err = digest(n); err != nil {
+				continue
+			}
+		}
+		ss.Files[n] = st
+	}
+
+	b, err := json.Marshal(ss)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o777); err != nil {
+		return err
+	}
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o666); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+// CatchUp compares files with the snapshot in name, and processes the files
+// which have been changed or created since it was written. Removed files are
+// not processed like the file system events.
+func (w *Watcher) CatchUp(name string) error {
+	b, err := os.ReadFile(name)
+	switch {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return err
+	}
+	var ss snapshot
+	if err := json.Unmarshal(b, &ss); err != nil {
+		return fmt.Errorf("%v: %w", name, err)
+	}
+
+	var files []string
+	for _, n := range w.files() {
+		fi, err := w.lstat(n)
+		if err != nil {
+			continue
+		}
+		switch st, ok := ss.Files[n]; {
+		case !ok:
+		case st.Size == fi.Size() && st.MTime.Equal(fi.ModTime()):
+			continue
+		case st.Hash != "" && st.Size == fi.Size():
+			if h, err := digest(n); err == nil && h == st.Hash {
+				continue
+			}
+		}
+		files = append(files, n)
+	}
+	if len(files) > 0 {
+		w.Trigger(files...)
+	}
+	return nil
+}
+
+// digest returns the SHA-256 digest of the file name.
+func digest(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// StateValue is a flag value to save the snapshot of files. It is "mtime",
+// "hash" or empty.
+type StateValue string
+
+func (s *StateValue) Set(v string) error {
+	if b, err := strconv.ParseBool(v); err == nil || v == "" {
+		if b {
+			*s = "mtime"
+		} else {
+			*s = ""
+		}
+		return nil
+	}
+	switch v {
+	case "mtime", "hash":
+		*s = StateValue(v)
+		return nil
+	}
+	return fmt.Errorf("invalid value %q", v)
+}
+
+func (s *StateValue) Get() any         { return string(*s) }
+func (s *StateValue) String() string   { return string(*s) }
+func (s *StateValue) IsBoolFlag() bool { return true }
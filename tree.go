@@ -0,0 +1,81 @@
+//
+// aster :: tree.go
+//
+//   Copyright (c) 2026 Akinori Hattori <hattya@gmail.com>
+//
+//   SPDX-License-Identifier: MIT
+//
+
+package aster
+
+import (
+	"os"
+	"strings"
+)
+
+// tree is a set of paths which is indexed by path components.
+type tree struct {
+	name     string // non-empty if it is a member
+	children map[string]*tree
+}
+
+func (t *tree) add(name string) {
+	n := t
+	for _, s := range split(name) {
+		c, ok := n.children[s]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[string]*tree)
+			}
+			c = new(tree)
+			n.children[s] = c
+		}
+		n = c
+	}
+	n.name = name
+}
+
+func (t *tree) contains(name string) bool {
+	n := t.lookup(split(name))
+	return n != nil && n.name != ""
+}
+
+// remove removes name and paths under it, and returns the removed paths.
+func (t *tree) remove(name string) (list []string) {
+	elems := split(name)
+	p := t.lookup(elems[:len(elems)-1])
+	if p == nil {
+		return
+	}
+	k := elems[len(elems)-1]
+	if n, ok := p.children[k]; ok {
+		delete(p.children, k)
+		n.walk(func(s string) {
+			list = append(list, s)
+		})
+	}
+	return
+}
+
+func (t *tree) lookup(elems []string) *tree {
+	n := t
+	for _, s := range elems {
+		if n = n.children[s]; n == nil {
+			break
+		}
+	}
+	return n
+}
+
+func (t *tree) walk(fn func(string)) {
+	if t.name != "" {
+		fn(t.name)
+	}
+	for _, c := range t.children {
+		c.walk(fn)
+	}
+}
+
+func split(name string) []string {
+	return strings.Split(name, string(os.PathSeparator))
+}
@@ -1,7 +1,7 @@
 //
 // aster :: util.go
 //
-//   Copyright (c) 2014-2025 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -9,7 +9,11 @@
 package aster
 
 import (
+	"context"
 	"io"
+	"log/slog"
+	"strconv"
+	"strings"
 
 	"github.com/hattya/go.cli"
 )
@@ -29,6 +33,21 @@ func trim(s string) string {
 	return s
 }
 
+// quote joins args into a command line.
+func quote(args []string) string {
+	var b strings.Builder
+	for i, s := range args {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		if s == "" || strings.ContainsAny(s, " \t\"'") {
+			s = strconv.Quote(s)
+		}
+		b.WriteString(s)
+	}
+	return b.String()
+}
+
 var discard io.WriteCloser = devNull(0)
 
 type devNull int
@@ -40,3 +59,11 @@ func (devNull) Write(p []byte) (int, error) {
 func (devNull) Close() error {
 	return nil
 }
+
+// discardHandler is a slog.Handler which discards all records.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
@@ -1,7 +1,7 @@
 //
 // aster :: watcher.go
 //
-//   Copyright (c) 2014-2020 Akinori Hattori <hattya@gmail.com>
+//   Copyright (c) 2014-2026 Akinori Hattori <hattya@gmail.com>
 //
 //   SPDX-License-Identifier: MIT
 //
@@ -10,8 +10,11 @@ package aster
 
 import (
 	"context"
+	"maps"
+	"net"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -23,31 +26,56 @@ import (
 
 type Watcher struct {
 	Squash time.Duration
+	// Digests is the maximum number of the digests of files which are
+	// cached to skip events of unchanged files. Zero disables it.
+	Digests int
 
-	ctx  context.Context
-	a    *Aster
-	w    *fsnotify.Watcher
-	quit chan struct{}
+	ctx    context.Context
+	a      *Aster
+	w      *fsnotify.Watcher
+	events chan fsnotify.Event // external events
+	eof    chan struct{}       // external events have been exhausted
+	quit   chan struct{}
+	fire   chan struct{}
+	all    atomic.Bool
+	reload atomic.Bool
+	batch  atomic.Int64 // number of processed batches
 
-	mu    sync.Mutex
-	paths map[string]struct{}
-	done  chan struct{}
+	mu       sync.Mutex
+	roots    []string
+	ln       net.Listener
+	ext      bool // events are read by Read
+	started  bool // Watch has been called
+	closed   bool
+	triggers []string
+	vcs      map[string]*vcsIgnore
+	links    *symlinks
+	paths    tree
+	done     chan struct{}
 }
 
-func NewWatcher(ctx context.Context, a *Aster) (*Watcher, error) {
+type WatcherOption func(*Watcher)
+
+func NewWatcher(ctx context.Context, a *Aster, options ...WatcherOption) (*Watcher, error) {
 	fsw, err := fsnotify.NewWatcher()
 	if err != nil {
 		return nil, err
 	}
 	w := &Watcher{
-		ctx:   ctx,
-		a:     a,
-		w:     fsw,
-		quit:  make(chan struct{}, 1),
-		paths: make(map[string]struct{}),
-		done:  make(chan struct{}),
-	}
-	if err := w.Update("."); err != nil {
+		ctx:    ctx,
+		a:      a,
+		w:      fsw,
+		events: make(chan fsnotify.Event),
+		eof:    make(chan struct{}),
+		quit:   make(chan struct{}, 1),
+		fire:   make(chan struct{}, 1),
+		vcs:    make(map[string]*vcsIgnore),
+		done:   make(chan struct{}),
+	}
+	for _, o := range options {
+		o(w)
+	}
+	if err := w.updateRoots(); err != nil {
 		fsw.Close()
 		return nil, err
 	}
@@ -56,21 +84,79 @@ func NewWatcher(ctx context.Context, a *Aster) (*Watcher, error) {
 
 func (w *Watcher) Close() error {
 	w.mu.Lock()
-	defer w.mu.Unlock()
 	select {
 	case <-w.done:
+		w.mu.Unlock()
 		return nil
 	default:
 	}
+	closed := w.closed
+	w.closed = true
+	started := w.started
+	if w.ln != nil {
+		w.ln.Close()
+	}
+	w.mu.Unlock()
 
+	switch {
+	case closed:
+		<-w.done
+		return nil
+	case !started:
+		close(w.done)
+		return w.w.Close()
+	}
+	// the Watch loop may acquire w.mu until it quits
 	w.quit <- struct{}{}
 	<-w.done
 	return w.w.Close()
 }
 
+// RunAll requests to invoke each watch with all of the matching files.
+func (w *Watcher) RunAll() {
+	w.all.Store(true)
+	w.signal()
+}
+
+// Trigger requests to process files as if they have been changed.
+func (w *Watcher) Trigger(files ...string) {
+	w.mu.Lock()
+	w.triggers = append(w.triggers, files...)
+	w.mu.Unlock()
+	w.signal()
+}
+
+// Reload requests to reload the Asterfile.
+func (w *Watcher) Reload() {
+	w.reload.Store(true)
+	w.signal()
+}
+
+// Pause suspends invoking callbacks. Events are kept until Resume is called.
+func (w *Watcher) Pause() {
+	w.a.Pause()
+}
+
+func (w *Watcher) Resume() {
+	if w.a.Resume() {
+		w.signal()
+	}
+}
+
+func (w *Watcher) Paused() bool {
+	return w.a.Paused()
+}
+
+func (w *Watcher) signal() {
+	select {
+	case w.fire <- struct{}{}:
+	default:
+	}
+}
+
 func (w *Watcher) Add(name string) error {
 	return w.walk(name, func(path string) error {
-		if w.a.Ignore(path) {
+		if w.ignore(path, true) {
 			return filepath.SkipDir
 		}
 		return w.add(path)
@@ -88,7 +174,7 @@ func (w *Watcher) Remove(name string) error {
 
 func (w *Watcher) Update(name string) error {
 	return w.walk(name, func(path string) error {
-		if w.a.Ignore(path) {
+		if w.ignore(path, true) {
 			if err := w.remove(path); err != nil {
 				return err
 			}
@@ -98,109 +184,477 @@ func (w *Watcher) Update(name string) error {
 	})
 }
 
+func (w *Watcher) updateRoots() error {
+	roots := w.a.Roots()
+	w.mu.Lock()
+	old := w.roots
+	w.roots = roots
+	w.links = newSymlinks(roots)
+	for _, r := range roots {
+		if vi, ok := w.vcs[r]; ok {
+			vi.Reset()
+		} else {
+			w.vcs[r] = newVCSIgnore(r)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, r := range old {
+		if !slices.Contains(roots, r) {
+			w.mu.Lock()
+			delete(w.vcs, r)
+			w.mu.Unlock()
+			if err := w.remove(r); err != nil {
+				return err
+			}
+			w.w.Remove(r)
+		}
+	}
+	if w.external() {
+		return nil
+	}
+	for _, r := range roots {
+		if err := w.Update(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Watcher) external() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.ext
+}
+
+// rel returns the root which contains name, and name relative to it.
+func (w *Watcher) rel(name string) (string, string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	root, rel := ".", name
+	for _, r := range w.roots {
+		switch {
+		case r == "." || len(r) <= len(root):
+		case name == r:
+			return r, "."
+		case strings.HasPrefix(name, r+string(os.PathSeparator)):
+			root, rel = r, name[len(r)+1:]
+		}
+	}
+	return root, rel
+}
+
+func (w *Watcher) ignore(name string, dir bool) bool {
+	root, rel := w.rel(name)
+	if w.a.Ignore(rel) || (!dir && w.a.TempFile(rel)) {
+		return true
+	}
+	if w.a.IgnoreVCS() {
+		if vi := w.vcsIgnore(root); vi != nil {
+			if rule := vi.Rule(rel, dir); rule != "" {
+				w.a.log.Debug("ignore", "name", rel, "pattern", rule)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (w *Watcher) vcsIgnore(root string) *vcsIgnore {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.vcs[root]
+}
+
 func (w *Watcher) add(name string) error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	w.paths[name] = struct{}{}
+	w.paths.add(name)
+	if w.ext {
+		return nil
+	}
 	return w.w.Add(name)
 }
 
-func (w *Watcher) remove(name string) error {
+func (w *Watcher) watched(name string) bool {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	if _, ok := w.paths[name]; ok {
-		delete(w.paths, name)
+	return w.paths.contains(name)
+}
 
-		name += string(os.PathSeparator)
-		for k := range w.paths {
-			if strings.HasPrefix(k, name) {
-				delete(w.paths, k)
-				if err := w.w.Remove(k); err != nil {
-					return err
-				}
-			}
+func (w *Watcher) remove(name string) (err error) {
+	for _, k := range w.prune(name) {
+		if e := w.w.Remove(k); e != nil && err == nil {
+			err = e
 		}
 	}
-	return nil
+	return
+}
+
+// prune removes name and paths under it from the tree, and returns the paths
+// which should be removed from fsnotify.
+func (w *Watcher) prune(name string) (list []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.links != nil {
+		w.links.forget(name)
+	}
+	for _, k := range w.paths.remove(name) {
+		if k != name {
+			list = append(list, k)
+		}
+	}
+	return
 }
 
 func (w *Watcher) walk(root string, fn func(string) error) error {
-	return walker.WalkWithContext(w.ctx, filepath.Clean(root), func(path string, fi os.FileInfo) error {
+	return w.walkTree(root, func(path string, fi os.FileInfo) error {
 		if !fi.IsDir() {
 			return nil
 		}
-		return fn(filepath.Clean(path))
+		return fn(path)
+	})
+}
+
+// scan returns files under name which are not ignored.
+func (w *Watcher) scan(name string) []string {
+	var mu sync.Mutex
+	var list []string
+	w.walkTree(name, func(path string, fi os.FileInfo) error {
+		switch {
+		case fi.IsDir():
+			if w.ignore(path, true) {
+				return filepath.SkipDir
+			}
+		case !w.ignore(path, false):
+			mu.Lock()
+			list = append(list, path)
+			mu.Unlock()
+		}
+		return nil
+	})
+	return list
+}
+
+// files returns all files under the roots which are not ignored.
+func (w *Watcher) files() []string {
+	w.mu.Lock()
+	roots := w.roots
+	w.mu.Unlock()
+
+	var list []string
+	for _, r := range roots {
+		list = append(list, w.scan(r)...)
+	}
+	slices.Sort(list)
+	return slices.Compact(list)
+}
+
+// walkTree walks the file tree rooted at root. Symlinked directories are
+// also walked when Aster follows symlinks.
+func (w *Watcher) walkTree(root string, fn func(string, os.FileInfo) error) error {
+	var l *symlinks
+	if w.a.FollowSymlinks() {
+		w.mu.Lock()
+		l = w.links
+		w.mu.Unlock()
+	}
+	return w.walkLinks(filepath.Clean(root), fn, l)
+}
+
+func (w *Watcher) walkLinks(root string, fn func(string, os.FileInfo) error, l *symlinks) error {
+	return walker.WalkWithContext(w.ctx, root, func(path string, fi os.FileInfo) error {
+		path = filepath.Clean(path)
+		if l != nil && fi.Mode()&os.ModeSymlink != 0 {
+			if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+				if !l.follow(path) {
+					// loop or already watched
+					return nil
+				}
+				// walk the target under the symlinked path
+				return w.walkLinks(path+string(os.PathSeparator)+".", fn, l)
+			}
+		}
+		return fn(path, fi)
 	})
 }
 
+// symlinks tracks real paths of watched directories, and it allows each
+// directory to be watched under only one path to avoid loops.
+type symlinks struct {
+	mu   sync.Mutex
+	seen map[string]string // real path → path
+}
+
+func newSymlinks(roots []string) *symlinks {
+	l := &symlinks{seen: make(map[string]string)}
+	for _, r := range roots {
+		l.follow(r)
+	}
+	return l
+}
+
+// follow reports whether the directory name should be walked.
+func (l *symlinks) follow(name string) bool {
+	real := realPath(name)
+	if real == "" {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for r, n := range l.seen {
+		switch {
+		case n == name:
+			if r == real {
+				return true
+			}
+			// target has been changed
+			delete(l.seen, r)
+		case within(real, r) || within(r, real):
+			return false
+		}
+	}
+	l.seen[real] = name
+	return true
+}
+
+// forget forgets name and paths under it.
+func (l *symlinks) forget(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for r, n := range l.seen {
+		if within(n, name) {
+			delete(l.seen, r)
+		}
+	}
+}
+
+func realPath(name string) string {
+	p, err := filepath.EvalSymlinks(name)
+	if err != nil {
+		return ""
+	}
+	if p, err = filepath.Abs(p); err != nil {
+		return ""
+	}
+	return p
+}
+
+// within reports whether name is dir or under it.
+func within(name, dir string) bool {
+	return name == dir || strings.HasPrefix(name, strings.TrimSuffix(dir, string(os.PathSeparator))+string(os.PathSeparator))
+}
+
 func (w *Watcher) Watch() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return w.ctx.Err()
+	}
+	w.started = true
+	w.mu.Unlock()
+
 	var mu sync.Mutex
 	dirs := make(map[string]struct{})
-	files := make(map[string]int)
-	fire := make(chan struct{}, 1)
+	queues := make(map[*watch]*queue)
+	var rename string
+	var renamed time.Time
+	var batch int64
 	done := make(chan struct{}, 1)
 	var retry int32
+	var dc *digests
+	if w.Digests > 0 {
+		dc = newDigests(w.Digests)
+		since := time.Now()
+		go func() {
+			for _, n := range w.files() {
+				dc.prime(n, since, w.lstat)
+			}
+			dc.primed()
+		}()
+	}
 
-	timer := time.AfterFunc(0, func() {
+	add := func(name, from string) {
+		k := w.a.receiver(name, from)
 		mu.Lock()
 		defer mu.Unlock()
 
-		if len(files) > 0 {
-			select {
-			case fire <- struct{}{}:
-			default:
+		q, ok := queues[k]
+		if !ok {
+			var s schedule
+			if k != nil {
+				s = k.schedule
 			}
+			q = newQueue(s)
+			queues[k] = q
 		}
-	})
+		q.files[name]++
+		if from != "" {
+			q.renames[name] = from
+		}
+		switch {
+		case q.ready:
+		case q.leading && q.idle(w.Squash):
+			w.a.log.Debug("leading", "name", name)
+			q.ready = true
+			w.signal()
+		default:
+			if !q.armed {
+				w.a.log.Debug("squash", "name", name, "delay", q.delay(w.Squash))
+			}
+			q.arm(w.Squash, func() {
+				mu.Lock()
+				ready := q.expire()
+				mu.Unlock()
+				if ready {
+					w.signal()
+				}
+			})
+		}
+	}
 
-	done <- struct{}{}
-	for {
-		select {
-		case ev := <-w.w.Events:
-			// remove "./" prefix
-			if len(ev.Name) > 2 && ev.Name[0] == '.' && os.IsPathSeparator(ev.Name[1]) {
-				ev.Name = ev.Name[2:]
-			}
-			// filter
-			switch {
-			case ev.Op&fsnotify.Create != 0:
-				switch fi, err := os.Lstat(ev.Name); {
-				case err != nil:
-					// removed immediately?
-					continue
-				case fi.IsDir():
+	event := func(ev fsnotify.Event) {
+		// remove "./" prefix
+		if len(ev.Name) > 2 && ev.Name[0] == '.' && os.IsPathSeparator(ev.Name[1]) {
+			ev.Name = ev.Name[2:]
+		}
+		w.a.log.Debug("event", "name", ev.Name, "op", ev.Op.String())
+		if dc != nil {
+			dc.touch(ev.Name)
+		}
+		// renamed file is reported as Rename and Create in a row, and they
+		// are paired in the same batch
+		from := rename
+		if from != "" && (w.batch.Load() != batch || time.Since(renamed) > w.delay(from)) {
+			from = ""
+		}
+		rename = ""
+		// filter
+		switch {
+		case ev.Op&fsnotify.Create != 0:
+			switch fi, err := w.lstat(ev.Name); {
+			case err != nil:
+				// removed immediately?
+				w.a.log.Debug("drop", "name", ev.Name, "reason", "removed")
+				return
+			case fi.IsDir():
+				if w.ignore(ev.Name, true) {
+					return
+				}
+				if w.a.watchDirs() {
+					add(ev.Name+string(os.PathSeparator), "")
+				}
+				go func() {
+					if err := w.Update(ev.Name); err != nil {
+						warn(w.a.ui, err)
+					}
+					// files in the new directory
+					for _, n := range w.scan(ev.Name) {
+						add(n, "")
+					}
+				}()
+				dirs[ev.Name] = struct{}{}
+				return
+			}
+		case ev.Op == fsnotify.Chmod:
+			w.a.log.Debug("drop", "name", ev.Name, "reason", "chmod")
+			return
+		default:
+			if ev.Op&(fsnotify.Remove|fsnotify.Rename) != 0 && w.watched(ev.Name) {
+				// directory has been removed or moved
+				paths := w.prune(ev.Name)
+				if w.a.FollowSymlinks() {
+					// target of the symlink may still exist
+					paths = append(paths, ev.Name)
+				}
+				if len(paths) > 0 {
+					// fsnotify.Watcher.Remove may wait for the goroutine
+					// which is sending events to this one
 					go func() {
-						if err := w.Update(ev.Name); err != nil {
-							warn(w.a.ui, err)
+						for _, k := range paths {
+							w.w.Remove(k)
 						}
 					}()
-					dirs[ev.Name] = struct{}{}
-					continue
 				}
-			case ev.Op == fsnotify.Chmod:
-				continue
-			default:
-				if _, ok := dirs[ev.Name]; ok {
-					continue
+				delete(dirs, ev.Name)
+				if w.a.watchDirs() {
+					add(ev.Name+string(os.PathSeparator), "")
 				}
+				return
+			}
+			if _, ok := dirs[ev.Name]; ok {
+				w.a.log.Debug("drop", "name", ev.Name, "reason", "directory")
+				return
+			}
+		}
+		// ignore rules have been changed
+		if w.a.IgnoreVCS() {
+			switch filepath.Base(ev.Name) {
+			case ".gitignore", ".hgignore":
+				go func() {
+					root, _ := w.rel(ev.Name)
+					if vi := w.vcsIgnore(root); vi != nil {
+						vi.Reset()
+					}
+					if err := w.Update(root); err != nil {
+						warn(w.a.ui, err)
+					}
+				}()
 			}
+		}
+		if w.ignore(ev.Name, false) {
+			return
+		}
 
+		switch {
+		case ev.Op&fsnotify.Remove != 0 || ev.Op&fsnotify.Rename != 0:
+			if dc != nil {
+				// file may be restored with the same contents
+				dc.remove(ev.Name)
+			}
 			mu.Lock()
-			n := len(files)
-			if ev.Op&fsnotify.Remove != 0 || ev.Op&fsnotify.Rename != 0 {
-				delete(dirs, ev.Name)
-				delete(files, ev.Name)
-			} else {
-				files[ev.Name]++
+			if ev.Op&fsnotify.Rename != 0 {
+				rename = ev.Name
+				renamed = time.Now()
+				batch = w.batch.Load()
 			}
-			mu.Unlock()
-			// new cycle has begun
-			if n == 0 {
-				timer.Reset(w.Squash)
+			delete(dirs, ev.Name)
+			for _, q := range queues {
+				if s, ok := q.renames[ev.Name]; ok && rename != "" {
+					rename = s
+				}
+				delete(q.files, ev.Name)
+				delete(q.renames, ev.Name)
 			}
-		case <-fire:
+			mu.Unlock()
+		case ev.Op&fsnotify.Create != 0:
+			add(ev.Name, from)
+		default:
+			add(ev.Name, "")
+		}
+	}
+
+	done <- struct{}{}
+	if w.a.RunAllRequested() {
+		w.RunAll()
+	}
+	eof := w.eof
+	for {
+		select {
+		case ev := <-w.w.Events:
+			event(ev)
+		case ev := <-w.events:
+			event(ev)
+		case <-w.fire:
 			go func() {
 				select {
 				case <-done:
@@ -210,29 +664,53 @@ func (w *Watcher) Watch() error {
 					return
 				}
 
-				// create snapshot & clear
-				mu.Lock()
-				ss := make(map[string]int)
-				for n, c := range files {
-					ss[n] = c
-					delete(files, n)
+				paused := w.a.Paused()
+				if w.reload.Swap(false) {
+					w.a.Reload()
+				}
+				if w.a.DropRequested() {
+					// drop events during the pause
+					mu.Lock()
+					for _, q := range queues {
+						clear(q.files)
+						clear(q.renames)
+						q.ready = false
+					}
+					mu.Unlock()
+				}
+				if !w.a.Paused() {
+					w.process(&mu, queues, dc)
 				}
-				mu.Unlock()
-				// process
-				w.a.OnChange(w.ctx, ss)
 				if w.a.Reloaded() {
-					if err := w.Update("."); err != nil {
+					if err := w.updateRoots(); err != nil {
 						warn(w.a.ui, err)
 					}
 				}
+				if w.a.RunAllRequested() {
+					w.RunAll()
+				}
 				done <- struct{}{}
 				// retry
-				if atomic.SwapInt32(&retry, 0) > 0 {
-					select {
-					case fire <- struct{}{}:
-					default:
-					}
+				if atomic.SwapInt32(&retry, 0) > 0 || (paused && !w.a.Paused()) {
+					w.signal()
+				}
+			}()
+		case <-eof:
+			eof = nil
+			// flush the pending files, and quit
+			mu.Lock()
+			for _, q := range queues {
+				q.stop()
+				q.ready = len(q.files) > 0
+			}
+			mu.Unlock()
+			go func() {
+				<-done
+				if !w.a.Paused() {
+					w.process(&mu, queues, dc)
 				}
+				done <- struct{}{}
+				w.Close()
 			}()
 		case err := <-w.w.Errors:
 			if err != nil {
@@ -240,11 +718,16 @@ func (w *Watcher) Watch() error {
 			}
 		case <-w.quit:
 			<-done
-			timer.Stop()
+			mu.Lock()
+			for _, q := range queues {
+				q.stop()
+			}
+			mu.Unlock()
 			atomic.SwapInt32(&retry, 0)
 			close(w.done)
 			return w.ctx.Err()
 		case <-w.ctx.Done():
+			w.a.Terminate()
 			select {
 			case w.quit <- struct{}{}:
 			default:
@@ -252,3 +735,89 @@ func (w *Watcher) Watch() error {
 		}
 	}
 }
+
+// process processes the ready queues.
+func (w *Watcher) process(mu *sync.Mutex, queues map[*watch]*queue, dc *digests) {
+	if w.all.Swap(false) {
+		w.a.RunAll(w.ctx, w.files())
+	}
+	// create snapshot & clear
+	mu.Lock()
+	ss := make(map[string]int)
+	rs := make(map[string]string)
+	now := time.Now()
+	for k, q := range queues {
+		if !q.ready {
+			if !q.armed && len(q.files) == 0 && !w.a.current(k) {
+				// Asterfile has been reloaded
+				delete(queues, k)
+			}
+			continue
+		}
+		maps.Copy(ss, q.files)
+		maps.Copy(rs, q.renames)
+		clear(q.files)
+		clear(q.renames)
+		q.ready = false
+		q.last = now
+	}
+	mu.Unlock()
+	// contents have not been changed
+	if dc != nil {
+		for n := range ss {
+			if _, ok := rs[n]; ok || strings.HasSuffix(n, string(os.PathSeparator)) {
+				continue
+			}
+			if fi, err := w.lstat(n); err == nil && fi.Mode().IsRegular() && !dc.changed(n, fi) {
+				w.a.log.Debug("drop", "name", n, "reason", "unchanged")
+				delete(ss, n)
+			}
+		}
+	}
+	w.mu.Lock()
+	for _, n := range w.triggers {
+		ss[filepath.Clean(n)]++
+	}
+	w.triggers = nil
+	w.mu.Unlock()
+	// process
+	if len(ss) > 0 {
+		w.a.OnChange(w.ctx, ss, rs)
+	}
+	w.batch.Add(1)
+}
+
+// delay returns the duration to wait after an event of name.
+func (w *Watcher) delay(name string) time.Duration {
+	var s schedule
+	if k := w.a.receiver(name, ""); k != nil {
+		s = k.schedule
+	}
+	return s.delay(w.Squash)
+}
+
+// lstat is like os.Lstat, but it returns the FileInfo of the target when
+// Aster follows symlinks.
+func (w *Watcher) lstat(name string) (os.FileInfo, error) {
+	fi, err := os.Lstat(name)
+	if err == nil && fi.Mode()&os.ModeSymlink != 0 && w.a.FollowSymlinks() {
+		if sfi, err := os.Stat(name); err == nil {
+			return sfi, nil
+		}
+	}
+	return fi, err
+}
+
+type RootValue []string
+
+func (r *RootValue) Set(s string) error {
+	if s == "" {
+		*r = nil
+	} else {
+		*r = append(*r, s)
+	}
+	return nil
+}
+
+func (r *RootValue) Get() any       { return []string(*r) }
+func (r *RootValue) String() string { return strings.Join(*r, string(os.PathListSeparator)) }